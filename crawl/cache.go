@@ -0,0 +1,35 @@
+package crawl
+
+import "sync"
+
+// RequestCache lets callers plug in persistent or shared storage for
+// previously-fetched response bodies, keyed by the request URL. New uses an
+// in-memory cache when Options.Cache is left nil.
+type RequestCache interface {
+	Get(url string) ([]byte, bool)
+	Set(url string, body []byte)
+}
+
+type memoryCache struct {
+	mu      sync.Mutex
+	entries map[string][]byte
+}
+
+// NewMemoryCache returns a RequestCache backed by an in-process map. It does
+// not persist across runs or share state across Collectors.
+func NewMemoryCache() RequestCache {
+	return &memoryCache{entries: map[string][]byte{}}
+}
+
+func (c *memoryCache) Get(url string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	body, ok := c.entries[url]
+	return body, ok
+}
+
+func (c *memoryCache) Set(url string, body []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[url] = body
+}
@@ -0,0 +1,326 @@
+// Package crawl wraps the parser and selector engine in a small, Colly-style
+// scraping API: register OnHTML/OnRequest/OnResponse/OnError callbacks on a
+// Collector, then Visit a URL. It exists so callers can write scrapers
+// directly against this module instead of pulling in a heavier framework.
+package crawl
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/html"
+
+	"github.com/EslamAhmed171/HTML-Parser/node"
+	"github.com/EslamAhmed171/HTML-Parser/selector"
+)
+
+// Options configures a Collector. The zero value is usable: synchronous,
+// unlimited depth, every domain allowed, an in-memory cache, and no
+// robots.txt checking.
+type Options struct {
+	// AllowedDomains restricts Visit to these hosts. A nil or empty slice
+	// allows every domain.
+	AllowedDomains []string
+
+	// MaxDepth caps the depth a page may be at; 0 means unlimited. The page
+	// passed to Visit is depth 1, so MaxDepth 1 fetches only that page.
+	MaxDepth int
+
+	// Async runs Visit (and Request.Visit) on a goroutine instead of
+	// blocking; call Collector.Wait to block until every queued visit
+	// finishes.
+	Async bool
+
+	// Parallelism caps how many fetches run concurrently while Async is
+	// set; 0 means unlimited.
+	Parallelism int
+
+	// UserAgent is sent with every request and used to pick the matching
+	// robots.txt group.
+	UserAgent string
+
+	// RespectRobotsTxt, when true, fetches each host's robots.txt once and
+	// skips pages it disallows for UserAgent.
+	RespectRobotsTxt bool
+
+	// Cache stores fetched bodies keyed by URL so a repeat visit skips the
+	// network. Defaults to an in-memory cache; provide one backed by disk
+	// or a shared store to persist across runs.
+	Cache RequestCache
+
+	// Client is the *http.Client used for every fetch, including
+	// robots.txt. Defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+type htmlCallback struct {
+	sel selector.Selector
+	fn  func(*Element)
+}
+
+// Collector crawls pages starting from Visit, parsing each response with
+// this module's own HTML parser and dispatching it to every callback whose
+// selector matches.
+type Collector struct {
+	opts Options
+
+	client *http.Client
+	cache  RequestCache
+	robots *robotsCache
+
+	mu      sync.Mutex
+	visited map[string]bool
+	sem     chan struct{}
+	wg      sync.WaitGroup
+
+	onRequest  []func(*Request)
+	onResponse []func(*Response)
+	onError    []func(*Response, error)
+	onHTML     []htmlCallback
+}
+
+// New returns a Collector configured by opts.
+func New(opts Options) *Collector {
+	client := opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	cache := opts.Cache
+	if cache == nil {
+		cache = NewMemoryCache()
+	}
+
+	c := &Collector{
+		opts:    opts,
+		client:  client,
+		cache:   cache,
+		visited: map[string]bool{},
+	}
+	if opts.RespectRobotsTxt {
+		c.robots = newRobotsCache(client)
+	}
+	if opts.Async && opts.Parallelism > 0 {
+		c.sem = make(chan struct{}, opts.Parallelism)
+	}
+	return c
+}
+
+// OnRequest registers fn to run immediately before each fetch.
+func (c *Collector) OnRequest(fn func(*Request)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onRequest = append(c.onRequest, fn)
+}
+
+// OnResponse registers fn to run after each successful fetch, before any
+// OnHTML callback sees the parsed document.
+func (c *Collector) OnResponse(fn func(*Response)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onResponse = append(c.onResponse, fn)
+}
+
+// OnError registers fn to run whenever a fetch fails, is blocked by
+// robots.txt, or returns a non-2xx status.
+func (c *Collector) OnError(fn func(*Response, error)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onError = append(c.onError, fn)
+}
+
+// OnHTML registers fn to run once per element matching sel in every parsed
+// response, in document order.
+func (c *Collector) OnHTML(sel string, fn func(*Element)) error {
+	compiled, err := selector.Compile(sel)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onHTML = append(c.onHTML, htmlCallback{sel: compiled, fn: fn})
+	return nil
+}
+
+// Visit fetches rawURL at depth 1, honoring AllowedDomains/MaxDepth/
+// robots.txt and dispatching the registered callbacks. When Async is set,
+// the fetch is queued on a goroutine and Visit returns immediately; call
+// Wait to block until it (and everything it queues) finishes.
+func (c *Collector) Visit(rawURL string) error {
+	return c.visit(rawURL, 1)
+}
+
+// Wait blocks until every Async-queued visit has completed.
+func (c *Collector) Wait() {
+	c.wg.Wait()
+}
+
+func (c *Collector) visit(rawURL string, depth int) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("crawl: invalid URL %q: %w", rawURL, err)
+	}
+
+	if c.opts.MaxDepth > 0 && depth > c.opts.MaxDepth {
+		return nil
+	}
+	if !c.domainAllowed(u.Host) {
+		return nil
+	}
+
+	c.mu.Lock()
+	if c.visited[u.String()] {
+		c.mu.Unlock()
+		return nil
+	}
+	c.visited[u.String()] = true
+	c.mu.Unlock()
+
+	req := &Request{URL: u, Depth: depth, collector: c}
+
+	if c.opts.Async {
+		c.wg.Add(1)
+		go func() {
+			defer c.wg.Done()
+			if c.sem != nil {
+				c.sem <- struct{}{}
+				defer func() { <-c.sem }()
+			}
+			c.fetch(req)
+		}()
+		return nil
+	}
+	return c.fetch(req)
+}
+
+func (c *Collector) domainAllowed(host string) bool {
+	if len(c.opts.AllowedDomains) == 0 {
+		return true
+	}
+	for _, d := range c.opts.AllowedDomains {
+		if strings.EqualFold(host, d) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Collector) fetch(req *Request) error {
+	for _, fn := range c.snapshotOnRequest() {
+		fn(req)
+	}
+
+	if c.robots != nil && !c.robots.allowed(req.URL, c.opts.UserAgent) {
+		err := fmt.Errorf("crawl: disallowed by robots.txt: %s", req.URL)
+		c.reportError(&Response{Request: req}, err)
+		return err
+	}
+
+	body, statusCode, err := c.bodyFor(req)
+	if err != nil {
+		c.reportError(&Response{Request: req}, err)
+		return err
+	}
+
+	resp := &Response{StatusCode: statusCode, Body: body, Request: req}
+	if statusCode < 200 || statusCode >= 300 {
+		err := fmt.Errorf("crawl: %s returned status %d", req.URL, statusCode)
+		c.reportError(resp, err)
+		return err
+	}
+
+	for _, fn := range c.snapshotOnResponse() {
+		fn(resp)
+	}
+
+	doc, err := html.Parse(strings.NewReader(string(body)))
+	if err != nil {
+		c.reportError(resp, err)
+		return err
+	}
+	root := node.RenderNode(doc, nil, node.ParserConfig{NormalizeWhitespace: true}, "", "")
+
+	for _, cb := range c.snapshotOnHTML() {
+		for _, n := range cb.sel.MatchAll(root) {
+			cb.fn(&Element{Node: n, Request: req})
+		}
+	}
+
+	return nil
+}
+
+// bodyFor returns req's cached body if present, otherwise fetches it over
+// HTTP and, on a 2xx response, stores it in the cache.
+func (c *Collector) bodyFor(req *Request) ([]byte, int, error) {
+	if body, ok := c.cache.Get(req.URL.String()); ok {
+		return body, http.StatusOK, nil
+	}
+
+	body, statusCode, err := c.doFetch(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	if statusCode >= 200 && statusCode < 300 {
+		c.cache.Set(req.URL.String(), body)
+	}
+	return body, statusCode, nil
+}
+
+func (c *Collector) doFetch(req *Request) ([]byte, int, error) {
+	httpReq, err := http.NewRequest(http.MethodGet, req.URL.String(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	if c.opts.UserAgent != "" {
+		httpReq.Header.Set("User-Agent", c.opts.UserAgent)
+	}
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+	return body, resp.StatusCode, nil
+}
+
+func (c *Collector) reportError(resp *Response, err error) {
+	for _, fn := range c.snapshotOnError() {
+		fn(resp, err)
+	}
+}
+
+// The snapshot* helpers copy a callback slice under lock so callbacks that
+// re-enter the Collector (e.g. calling Request.Visit synchronously) never
+// invoke Visit while c.mu is held.
+
+func (c *Collector) snapshotOnRequest() []func(*Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]func(*Request){}, c.onRequest...)
+}
+
+func (c *Collector) snapshotOnResponse() []func(*Response) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]func(*Response){}, c.onResponse...)
+}
+
+func (c *Collector) snapshotOnError() []func(*Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]func(*Response, error){}, c.onError...)
+}
+
+func (c *Collector) snapshotOnHTML() []htmlCallback {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]htmlCallback{}, c.onHTML...)
+}
@@ -0,0 +1,95 @@
+package crawl
+
+import (
+	"strings"
+
+	"github.com/EslamAhmed171/HTML-Parser/node"
+	"github.com/EslamAhmed171/HTML-Parser/selector"
+)
+
+// Element wraps one HTMLNode matched by an OnHTML selector, together with
+// the Request it was found on so a callback can both read the DOM and queue
+// further crawling via Element.Request.Visit.
+type Element struct {
+	Node    *node.HTMLNode
+	Request *Request
+}
+
+// Attr returns the named attribute's value, or "" if the element doesn't
+// carry it.
+func (e *Element) Attr(name string) string {
+	return e.Node.Attributes[name]
+}
+
+// Text returns the concatenated text content of the element's subtree.
+func (e *Element) Text() string {
+	return textOf(e.Node)
+}
+
+// ChildText returns the text of the first descendant matching sel, or "" if
+// none match or sel doesn't compile.
+func (e *Element) ChildText(sel string) string {
+	child := firstMatch(e.Node, sel)
+	if child == nil {
+		return ""
+	}
+	return textOf(child)
+}
+
+// ChildAttr returns the named attribute of the first descendant matching
+// sel, or "" if none match.
+func (e *Element) ChildAttr(sel, name string) string {
+	child := firstMatch(e.Node, sel)
+	if child == nil {
+		return ""
+	}
+	return child.Attributes[name]
+}
+
+// ForEach calls fn once per descendant matching sel, in document order. The
+// element's own node is never matched, even if sel would otherwise match it.
+func (e *Element) ForEach(sel string, fn func(int, *Element)) {
+	compiled, err := selector.Compile(sel)
+	if err != nil {
+		return
+	}
+	i := 0
+	for _, c := range e.Node.Children {
+		for _, n := range compiled.MatchAll(c) {
+			fn(i, &Element{Node: n, Request: e.Request})
+			i++
+		}
+	}
+}
+
+// firstMatch returns the first node matching sel among root's descendants,
+// in document order. root itself is never considered a candidate: callers
+// use this for "Child*" lookups, where a selector matching the element's
+// own tag must not short-circuit on the element itself.
+func firstMatch(root *node.HTMLNode, sel string) *node.HTMLNode {
+	compiled, err := selector.Compile(sel)
+	if err != nil {
+		return nil
+	}
+	for _, c := range root.Children {
+		if matches := compiled.MatchAll(c); len(matches) > 0 {
+			return matches[0]
+		}
+	}
+	return nil
+}
+
+func textOf(n *node.HTMLNode) string {
+	var b strings.Builder
+	var walk func(n *node.HTMLNode)
+	walk = func(n *node.HTMLNode) {
+		if n.Type == "text" {
+			b.WriteString(n.TextContent)
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(n)
+	return b.String()
+}
@@ -0,0 +1,61 @@
+package crawl
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+
+	"github.com/EslamAhmed171/HTML-Parser/node"
+	"github.com/EslamAhmed171/HTML-Parser/selector"
+)
+
+func elementForTest(t *testing.T, src, sel string) *Element {
+	t.Helper()
+	doc, err := html.Parse(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("html.Parse: %v", err)
+	}
+	root := node.RenderNode(doc, nil, node.ParserConfig{NormalizeWhitespace: true}, "", "")
+	compiled, err := selector.Compile(sel)
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	matches := compiled.MatchAll(root)
+	if len(matches) == 0 {
+		t.Fatalf("selector %q matched nothing", sel)
+	}
+	return &Element{Node: matches[0]}
+}
+
+func TestChildTextExcludesElementItself(t *testing.T) {
+	e := elementForTest(t, `<table><tr><td>own text</td></tr></table>`, "td")
+	if got := e.ChildText("td"); got != "" {
+		t.Errorf("ChildText(\"td\") on a <td> with no nested <td> = %q, want \"\"", got)
+	}
+}
+
+func TestChildTextFindsDescendant(t *testing.T) {
+	e := elementForTest(t, `<div><h1>Title</h1><p>body</p></div>`, "div")
+	if got := e.ChildText("h1"); got != "Title" {
+		t.Errorf("ChildText(\"h1\") = %q, want %q", got, "Title")
+	}
+}
+
+func TestChildAttrExcludesElementItself(t *testing.T) {
+	e := elementForTest(t, `<a href="/self">text</a>`, "a")
+	if got := e.ChildAttr("a", "href"); got != "" {
+		t.Errorf("ChildAttr(\"a\", \"href\") on an <a> with no nested <a> = %q, want \"\"", got)
+	}
+}
+
+func TestForEachExcludesElementItself(t *testing.T) {
+	e := elementForTest(t, `<ul><li>a</li><li>b</li></ul>`, "ul")
+	var got []string
+	e.ForEach("li", func(i int, child *Element) {
+		got = append(got, child.Text())
+	})
+	if len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Errorf("ForEach(\"li\") = %v, want [a b]", got)
+	}
+}
@@ -0,0 +1,29 @@
+package crawl
+
+import "net/url"
+
+// Request describes one page fetch: its URL and crawl depth, plus a handle
+// back to the Collector so OnHTML/OnRequest/OnResponse callbacks can queue
+// further visits relative to it.
+type Request struct {
+	URL   *url.URL
+	Depth int
+
+	collector *Collector
+}
+
+// AbsoluteURL resolves rel (which may be relative, e.g. an <a href>) against
+// the request's own URL. It returns "" if rel cannot be parsed as a URL.
+func (r *Request) AbsoluteURL(rel string) string {
+	u, err := url.Parse(rel)
+	if err != nil {
+		return ""
+	}
+	return r.URL.ResolveReference(u).String()
+}
+
+// Visit resolves rel against this request's URL and queues it for crawling
+// one depth level deeper.
+func (r *Request) Visit(rel string) error {
+	return r.collector.visit(r.AbsoluteURL(rel), r.Depth+1)
+}
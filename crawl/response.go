@@ -0,0 +1,9 @@
+package crawl
+
+// Response is the result of fetching a Request: the raw body plus the
+// status code it came back with.
+type Response struct {
+	StatusCode int
+	Body       []byte
+	Request    *Request
+}
@@ -0,0 +1,162 @@
+package crawl
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// robotsGroup is one "User-agent:" group's Allow/Disallow rules.
+type robotsGroup struct {
+	agent    string
+	disallow []string
+	allow    []string
+}
+
+// hostRobots holds every group parsed from one host's robots.txt.
+type hostRobots struct {
+	groups []robotsGroup
+}
+
+// robotsCache fetches and caches robots.txt per host so RespectRobotsTxt
+// costs one extra request per host rather than one per page.
+type robotsCache struct {
+	client *http.Client
+
+	mu    sync.Mutex
+	hosts map[string]*hostRobots
+}
+
+func newRobotsCache(client *http.Client) *robotsCache {
+	return &robotsCache{client: client, hosts: map[string]*hostRobots{}}
+}
+
+func (r *robotsCache) allowed(u *url.URL, userAgent string) bool {
+	r.mu.Lock()
+	hr, ok := r.hosts[u.Host]
+	r.mu.Unlock()
+
+	if !ok {
+		hr = r.fetch(u)
+		r.mu.Lock()
+		r.hosts[u.Host] = hr
+		r.mu.Unlock()
+	}
+	return hr.allowed(u.Path, userAgent)
+}
+
+// fetch requests robots.txt for u's host. Any failure to fetch or parse it
+// is treated as an empty rule set (everything allowed), matching how most
+// crawlers behave when a site has no robots.txt at all.
+func (r *robotsCache) fetch(u *url.URL) *hostRobots {
+	robotsURL := (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/robots.txt"}).String()
+	resp, err := r.client.Get(robotsURL)
+	if err != nil {
+		return &hostRobots{}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return &hostRobots{}
+	}
+	return parseRobots(resp.Body)
+}
+
+func parseRobots(r io.Reader) *hostRobots {
+	hr := &hostRobots{}
+	// pending holds the indices (into hr.groups) of the groups created by
+	// the current run of consecutive "User-agent:" lines; any
+	// Allow/Disallow that follows applies to all of them, since that's the
+	// common idiom for sharing one rule block across several agents.
+	// Indices, rather than pointers, survive hr.groups growing and
+	// reallocating as more groups are appended. A "user-agent" line right
+	// after a rule (rather than another "user-agent" line) starts a new
+	// run, so it resets pending instead of joining the previous one.
+	var pending []int
+	inAgentRun := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if !inAgentRun {
+				pending = nil
+			}
+			hr.groups = append(hr.groups, robotsGroup{agent: strings.ToLower(value)})
+			pending = append(pending, len(hr.groups)-1)
+			inAgentRun = true
+		case "disallow":
+			inAgentRun = false
+			if value != "" {
+				for _, idx := range pending {
+					hr.groups[idx].disallow = append(hr.groups[idx].disallow, value)
+				}
+			}
+		case "allow":
+			inAgentRun = false
+			if value != "" {
+				for _, idx := range pending {
+					hr.groups[idx].allow = append(hr.groups[idx].allow, value)
+				}
+			}
+		default:
+			inAgentRun = false
+		}
+	}
+	return hr
+}
+
+// allowed reports whether path may be fetched by userAgent, using the
+// longest-matching-rule precedence the Robots Exclusion Protocol defines
+// between Allow and Disallow within a group.
+func (hr *hostRobots) allowed(path, userAgent string) bool {
+	group := hr.groupFor(userAgent)
+	if group == nil {
+		return true
+	}
+
+	bestLen := -1
+	bestAllow := true
+	for _, d := range group.disallow {
+		if strings.HasPrefix(path, d) && len(d) > bestLen {
+			bestLen, bestAllow = len(d), false
+		}
+	}
+	for _, a := range group.allow {
+		if strings.HasPrefix(path, a) && len(a) > bestLen {
+			bestLen, bestAllow = len(a), true
+		}
+	}
+	return bestAllow
+}
+
+// groupFor picks the group matching userAgent, falling back to the
+// wildcard "*" group.
+func (hr *hostRobots) groupFor(userAgent string) *robotsGroup {
+	ua := strings.ToLower(userAgent)
+	var wildcard *robotsGroup
+	for i := range hr.groups {
+		g := &hr.groups[i]
+		if g.agent == "*" {
+			wildcard = g
+			continue
+		}
+		if ua != "" && strings.Contains(ua, g.agent) {
+			return g
+		}
+	}
+	return wildcard
+}
@@ -0,0 +1,58 @@
+package crawl
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseRobotsSharedGroup(t *testing.T) {
+	// A common robots.txt idiom: several "User-agent" lines in a row share
+	// the rules that follow them.
+	const robotsTxt = `User-agent: AgentA
+User-agent: AgentB
+Disallow: /private
+
+User-agent: AgentC
+Disallow: /admin
+`
+	hr := parseRobots(strings.NewReader(robotsTxt))
+
+	for _, agent := range []string{"AgentA", "AgentB"} {
+		if hr.allowed("/private/secret", agent) {
+			t.Errorf("%s: want /private/secret disallowed, got allowed", agent)
+		}
+		if !hr.allowed("/admin/panel", agent) {
+			t.Errorf("%s: want /admin/panel allowed, got disallowed", agent)
+		}
+	}
+	if hr.allowed("/admin/panel", "AgentC") {
+		t.Error("AgentC: want /admin/panel disallowed, got allowed")
+	}
+	if !hr.allowed("/private/secret", "AgentC") {
+		t.Error("AgentC: want /private/secret allowed, got disallowed")
+	}
+}
+
+func TestParseRobotsSeparateGroups(t *testing.T) {
+	// User-agent lines NOT separated only by blank lines/comments, each
+	// followed directly by its own rule, must stay independent.
+	const robotsTxt = `User-agent: AgentA
+Disallow: /a
+User-agent: AgentB
+Disallow: /b
+`
+	hr := parseRobots(strings.NewReader(robotsTxt))
+
+	if !hr.allowed("/b/x", "AgentA") {
+		t.Error("AgentA: want /b/x allowed, got disallowed")
+	}
+	if hr.allowed("/a/x", "AgentA") {
+		t.Error("AgentA: want /a/x disallowed, got allowed")
+	}
+	if !hr.allowed("/a/x", "AgentB") {
+		t.Error("AgentB: want /a/x allowed, got disallowed")
+	}
+	if hr.allowed("/b/x", "AgentB") {
+		t.Error("AgentB: want /b/x disallowed, got allowed")
+	}
+}
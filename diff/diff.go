@@ -0,0 +1,606 @@
+// Package diff computes a minimal-cost edit script between two
+// *node.HTMLNode trees using the Zhang-Shasha tree edit distance algorithm,
+// and can apply the resulting patch back onto a tree.
+package diff
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+
+	"github.com/EslamAhmed171/HTML-Parser/node"
+)
+
+// OpType identifies the kind of change recorded in an Operation.
+type OpType string
+
+const (
+	OpInsert OpType = "insert"
+	OpDelete OpType = "delete"
+	OpUpdate OpType = "update"
+	OpMove   OpType = "move"
+)
+
+// Operation is one step of an edit script. Path/NewPath are derived
+// SelectorPath strings kept for display/debugging only; they're ambiguous
+// whenever siblings share structure (any list renders the same path for
+// every item), so Apply never uses them to locate a target. Addressing
+// instead rides the live node pointers captured at Compute time: Node/
+// Before/After already point into tree A or B, and Patch's internal
+// node-correspondence table bridges an Insert/Move's tree-B destination
+// back to its tree-A counterpart. That only works if Apply is given the
+// very same tree A passed to Compute, not a structurally-similar copy.
+type Operation struct {
+	Type    OpType         `json:"type"`
+	Path    string         `json:"path"`              // node this op targets, in tree A (Delete/Update/Move source) or tree B (Insert), for display only
+	NewPath string         `json:"newPath,omitempty"` // destination path for Move, for display only
+	Node    *node.HTMLNode `json:"node,omitempty"`     // inserted subtree (Insert) or move source (Move), in its origin tree
+	Before  *node.HTMLNode `json:"before,omitempty"`   // prior node snapshot (Update), in tree A
+	After   *node.HTMLNode `json:"after,omitempty"`    // new node snapshot (Update), in tree B
+	Cost    float64        `json:"cost,omitempty"`
+
+	// destParent is the tree-B parent a Move should reattach under. It has
+	// no tree-A counterpart of its own (Node is the tree-A source being
+	// moved), so Apply resolves it through Patch.matches/created instead.
+	destParent *node.HTMLNode
+}
+
+// Patch is a JSON-serializable edit script mapping tree A onto tree B.
+type Patch struct {
+	Ops []Operation `json:"ops"`
+
+	// matches maps each tree-B node Compute found a corresponding tree-A
+	// node for (including unchanged/updated nodes, not just ones named by
+	// an Operation) to that tree-A node. Apply uses it to resolve Insert/
+	// Move destinations, which only exist as tree-B pointers. Unexported:
+	// it's an implementation detail of the same-process Compute/Apply
+	// round trip, not part of the patch's serialized form.
+	matches map[*node.HTMLNode]*node.HTMLNode
+}
+
+// Options configures diff computation.
+type Options struct {
+	// SubtreeHashing hashes every subtree (tag + sorted attrs + child
+	// hashes) up front and uses that in three places: an O(1) short-circuit
+	// when the whole trees are identical, an O(1) zero-cost shortcut for
+	// each node comparison the Zhang-Shasha DP makes, and, during
+	// backtracking, skipping straight to a zero-cost 1:1 match (no
+	// re-running the DP) for any subtree whose hash matches — so a small
+	// change deep inside an otherwise-unchanged subtree doesn't cost
+	// re-walking that subtree during reconstruction. It does not prune the
+	// keyroot-pair forest-distance table itself, so the up-front DP that
+	// feeds backtracking still costs the full comparison once per
+	// keyroot pair regardless of hashing. Equal-hash delete/insert pairs
+	// are also fused into a single Move operation afterwards.
+	SubtreeHashing bool
+}
+
+// Compute returns the minimal-cost edit script mapping a onto b.
+func Compute(a, b *node.HTMLNode, opts Options) (*Patch, error) {
+	if a == nil || b == nil {
+		return nil, fmt.Errorf("diff: both trees must be non-nil")
+	}
+
+	var hashes map[*node.HTMLNode]uint64
+	if opts.SubtreeHashing {
+		hashes = make(map[*node.HTMLNode]uint64)
+		hashSubtree(a, hashes)
+		hashSubtree(b, hashes)
+		if hashes[a] == hashes[b] {
+			return &Patch{}, nil
+		}
+	}
+
+	t1 := indexTree(a)
+	t2 := indexTree(b)
+	n, m := len(t1.nodes)-1, len(t2.nodes)-1
+
+	td := make([][]float64, n+1)
+	for i := range td {
+		td[i] = make([]float64, m+1)
+	}
+	for _, i := range t1.keyroots {
+		for _, j := range t2.keyroots {
+			treedist(t1, t2, hashes, i, j, td)
+		}
+	}
+
+	var ops []Operation
+	matches := map[*node.HTMLNode]*node.HTMLNode{}
+	backtrackForest(t1, t2, hashes, td, n, m, matches, &ops)
+
+	if opts.SubtreeHashing {
+		ops = fuseMoves(ops, hashes)
+	}
+
+	return &Patch{Ops: ops, matches: matches}, nil
+}
+
+// Apply re-plays a patch's operations onto root, returning the resulting
+// tree. root must be the very same tree A passed to Compute, not merely a
+// structurally similar copy: Delete/Update/Move-source target their node
+// directly via the pointer Compute captured (Node/Before), which is
+// unambiguous even when siblings share identical structure (and thus an
+// identical SelectorPath) — the ambiguity a string-keyed lookup can't avoid.
+// Insert/Move destinations, which only exist as tree-B pointers, are
+// resolved through Patch's internal node-correspondence table plus a
+// running table of nodes this call has itself just inserted.
+func Apply(root *node.HTMLNode, p *Patch) (*node.HTMLNode, error) {
+	// created maps a tree-B node materialized earlier in this Apply call to
+	// its new tree-A node, so a later op targeting one of its descendants
+	// (e.g. a child's own Insert op) can find it even though Compute never
+	// saw it (it didn't exist in tree A).
+	created := map[*node.HTMLNode]*node.HTMLNode{}
+	resolve := func(bNode *node.HTMLNode) *node.HTMLNode {
+		if a, ok := created[bNode]; ok {
+			return a
+		}
+		return p.matches[bNode]
+	}
+
+	for _, op := range p.Ops {
+		switch op.Type {
+		case OpDelete:
+			target := op.Node
+			if target == nil || target.Parent == nil {
+				continue
+			}
+			detach(target)
+		case OpMove:
+			target := op.Node
+			if target == nil || target.Parent == nil {
+				return nil, fmt.Errorf("diff: apply: move source missing for %q", op.Path)
+			}
+			dest := resolve(op.destParent)
+			if dest == nil {
+				return nil, fmt.Errorf("diff: apply: move destination not found for %q", op.NewPath)
+			}
+			detach(target)
+			target.Parent = dest
+			dest.Children = append(dest.Children, target)
+			node.RecomputePaths(target)
+		case OpInsert:
+			dest := resolve(op.Node.Parent)
+			if dest == nil {
+				return nil, fmt.Errorf("diff: apply: insert target not found for %q", op.Path)
+			}
+			// Only the node itself is materialized here: if it had
+			// children in tree B, those arrive as their own Insert
+			// operations (backtracking emits them in reverse postorder, so
+			// the parent already exists by the time a child's op runs).
+			inserted := shallowCloneNode(op.Node)
+			inserted.Parent = dest
+			dest.Children = append(dest.Children, inserted)
+			node.RecomputePaths(inserted)
+			created[op.Node] = inserted
+		case OpUpdate:
+			target := op.Before
+			if target == nil {
+				return nil, fmt.Errorf("diff: apply: update target missing for %q", op.Path)
+			}
+			if op.After != nil {
+				target.TextContent = op.After.TextContent
+				target.Attributes = op.After.Attributes
+				target.ClassList = op.After.ClassList
+				target.ID = op.After.ID
+			}
+		}
+	}
+	return root, nil
+}
+
+func detach(n *node.HTMLNode) {
+	p := n.Parent
+	var kept []*node.HTMLNode
+	for _, c := range p.Children {
+		if c != n {
+			kept = append(kept, c)
+		}
+	}
+	p.Children = kept
+	n.Parent = nil
+}
+
+// shallowCloneNode copies a node's own fields (tag/id/classes/attrs/text)
+// without its children: children of an inserted subtree arrive as their own
+// Insert operations and get attached to this node once it exists.
+func shallowCloneNode(n *node.HTMLNode) *node.HTMLNode {
+	if n == nil {
+		return nil
+	}
+	clone := &node.HTMLNode{
+		Type:        n.Type,
+		TagName:     n.TagName,
+		TextContent: n.TextContent,
+		ID:          n.ID,
+	}
+	if n.Attributes != nil {
+		clone.Attributes = make(map[string]string, len(n.Attributes))
+		for k, v := range n.Attributes {
+			clone.Attributes[k] = v
+		}
+	}
+	clone.ClassList = append([]string(nil), n.ClassList...)
+	return clone
+}
+
+// --- Zhang-Shasha core ---
+
+type treeIndex struct {
+	nodes    []*node.HTMLNode // postorder, 1-indexed; nodes[0] is a dummy
+	l        []int            // l[i] = postorder index of the leftmost leaf descendant of node i
+	keyroots []int
+}
+
+func indexTree(root *node.HTMLNode) *treeIndex {
+	ti := &treeIndex{nodes: []*node.HTMLNode{nil}, l: []int{0}}
+
+	var maxSeenAtL = map[int]int{}
+
+	var post func(n *node.HTMLNode) int
+	post = func(n *node.HTMLNode) int {
+		childIdxs := make([]int, 0, len(n.Children))
+		for _, c := range n.Children {
+			childIdxs = append(childIdxs, post(c))
+		}
+		ti.nodes = append(ti.nodes, n)
+		idx := len(ti.nodes) - 1
+
+		lm := idx
+		if len(childIdxs) > 0 {
+			lm = ti.l[childIdxs[0]]
+		}
+		ti.l = append(ti.l, lm)
+		maxSeenAtL[lm] = idx
+		return idx
+	}
+	post(root)
+
+	for _, idx := range maxSeenAtL {
+		ti.keyroots = append(ti.keyroots, idx)
+	}
+	sort.Ints(ti.keyroots)
+
+	return ti
+}
+
+func delCost(*node.HTMLNode) float64 { return 1 }
+func insCost(*node.HTMLNode) float64 { return 1 }
+
+func updCost(a, b *node.HTMLNode, hashes map[*node.HTMLNode]uint64) float64 {
+	if hashes != nil && hashes[a] == hashes[b] {
+		return 0
+	}
+	if a.Type != b.Type {
+		return 1
+	}
+	if a.Type == "text" {
+		return levenshteinNormalized(a.TextContent, b.TextContent)
+	}
+	if a.Type != "element" {
+		return 0
+	}
+	if a.TagName != b.TagName {
+		return 1
+	}
+
+	cost := 0.0
+	if a.ID != b.ID {
+		cost += 0.25
+	}
+	cost += 0.25 * symmetricDiffRatio(a.ClassList, b.ClassList)
+	cost += 0.25 * attrDiffRatio(a.Attributes, b.Attributes)
+	cost += 0.25 * levenshteinNormalized(ownText(a), ownText(b))
+	if cost > 1 {
+		cost = 1
+	}
+	return cost
+}
+
+func min3(a, b, c float64) float64 {
+	m := a
+	if b < m {
+		m = b
+	}
+	if c < m {
+		m = c
+	}
+	return m
+}
+
+// treedist fills td[i][j] (the tree-edit distance between the subtrees
+// rooted at i and j) along with every forest-distance subproblem needed to
+// reach it, per the standard Zhang-Shasha recurrence.
+func treedist(t1, t2 *treeIndex, hashes map[*node.HTMLNode]uint64, i, j int, td [][]float64) {
+	fdTable(t1, t2, hashes, i, j, td)
+}
+
+// fdTable (re)computes the forest-distance table for the (i, j) keyroot
+// pair, writing tree distances for every complete-subtree cell into td, and
+// returns the table for reuse during backtracking.
+func fdTable(t1, t2 *treeIndex, hashes map[*node.HTMLNode]uint64, i, j int, td [][]float64) [][]float64 {
+	li, lj := t1.l[i], t2.l[j]
+	rows, cols := i-li+2, j-lj+2
+
+	fd := make([][]float64, rows)
+	for x := range fd {
+		fd[x] = make([]float64, cols)
+	}
+
+	for x := 1; x < rows; x++ {
+		ix := li + x - 1
+		fd[x][0] = fd[x-1][0] + delCost(t1.nodes[ix])
+	}
+	for y := 1; y < cols; y++ {
+		jy := lj + y - 1
+		fd[0][y] = fd[0][y-1] + insCost(t2.nodes[jy])
+	}
+
+	for x := 1; x < rows; x++ {
+		ix := li + x - 1
+		for y := 1; y < cols; y++ {
+			jy := lj + y - 1
+			del := fd[x-1][y] + delCost(t1.nodes[ix])
+			ins := fd[x][y-1] + insCost(t2.nodes[jy])
+			if t1.l[ix] == li && t2.l[jy] == lj {
+				upd := fd[x-1][y-1] + updCost(t1.nodes[ix], t2.nodes[jy], hashes)
+				cost := min3(del, ins, upd)
+				fd[x][y] = cost
+				td[ix][jy] = cost
+			} else {
+				mix := fd[t1.l[ix]-li][t2.l[jy]-lj] + td[ix][jy]
+				fd[x][y] = min3(del, ins, mix)
+			}
+		}
+	}
+	return fd
+}
+
+// backtrackForest recovers the edit script for the (i, j) complete-subtree
+// pair, recording each tree-A/tree-B node correspondence it discovers into
+// matches (even when the nodes are identical and no Operation is emitted)
+// so Apply can later resolve Insert/Move destinations, which only exist as
+// tree-B pointers, back to their tree-A counterpart.
+func backtrackForest(t1, t2 *treeIndex, hashes map[*node.HTMLNode]uint64, td [][]float64, i, j int, matches map[*node.HTMLNode]*node.HTMLNode, ops *[]Operation) {
+	// Equal subtree hashes mean the subtrees rooted at i and j are
+	// structurally identical, so the optimal alignment is the obvious 1:1
+	// correspondence between them at every depth, at zero cost. Recording
+	// that directly, instead of re-deriving it through fdTable, is what
+	// lets a single small change deep inside an otherwise-huge, unchanged
+	// subtree skip re-walking that subtree's DP table during backtracking.
+	if hashes != nil && hashes[t1.nodes[i]] == hashes[t2.nodes[j]] {
+		matchIdenticalSubtrees(t1.nodes[i], t2.nodes[j], matches)
+		return
+	}
+
+	li, lj := t1.l[i], t2.l[j]
+	fd := fdTable(t1, t2, hashes, i, j, td)
+
+	x, y := i-li+1, j-lj+1
+	for x > 0 || y > 0 {
+		ix, jy := li+x-1, lj+y-1
+		switch {
+		case x > 0 && y > 0 && t1.l[ix] == li && t2.l[jy] == lj &&
+			fd[x][y] == fd[x-1][y-1]+updCost(t1.nodes[ix], t2.nodes[jy], hashes):
+			matches[t2.nodes[jy]] = t1.nodes[ix]
+			if c := updCost(t1.nodes[ix], t2.nodes[jy], hashes); c > 0 {
+				*ops = append(*ops, Operation{
+					Type: OpUpdate, Path: t1.nodes[ix].SelectorPath,
+					Before: t1.nodes[ix], After: t2.nodes[jy], Cost: c,
+				})
+			}
+			x--
+			y--
+		case x > 0 && y > 0 && (t1.l[ix] != li || t2.l[jy] != lj) &&
+			fd[x][y] == fd[t1.l[ix]-li][t2.l[jy]-lj]+td[ix][jy]:
+			backtrackForest(t1, t2, hashes, td, ix, jy, matches, ops)
+			x = t1.l[ix] - li
+			y = t2.l[jy] - lj
+		case x > 0 && fd[x][y] == fd[x-1][y]+delCost(t1.nodes[ix]):
+			*ops = append(*ops, Operation{Type: OpDelete, Path: t1.nodes[ix].SelectorPath, Node: t1.nodes[ix], Cost: 1})
+			x--
+		case y > 0 && fd[x][y] == fd[x][y-1]+insCost(t2.nodes[jy]):
+			*ops = append(*ops, Operation{Type: OpInsert, Path: parentPath(t2.nodes[jy]), Node: t2.nodes[jy], Cost: 1})
+			y--
+		default:
+			// Numerical tie with no exact match; fall back to whichever
+			// move keeps progressing.
+			if x > 0 {
+				x--
+			} else {
+				y--
+			}
+		}
+	}
+}
+
+// matchIdenticalSubtrees records a into matches for b and recurses pairwise
+// into their children (same count and order, since a and b are already
+// known to be structurally identical): no Operation is needed for any node
+// in the pair, since nothing differs.
+func matchIdenticalSubtrees(a, b *node.HTMLNode, matches map[*node.HTMLNode]*node.HTMLNode) {
+	matches[b] = a
+	for k := range a.Children {
+		matchIdenticalSubtrees(a.Children[k], b.Children[k], matches)
+	}
+}
+
+func parentPath(n *node.HTMLNode) string {
+	if n.Parent == nil {
+		return ""
+	}
+	return n.Parent.SelectorPath
+}
+
+// fuseMoves collapses a Delete of subtree A and an Insert of subtree B with
+// an identical subtree hash into a single Move operation.
+func fuseMoves(ops []Operation, hashes map[*node.HTMLNode]uint64) []Operation {
+	if hashes == nil {
+		return ops
+	}
+
+	consumed := make([]bool, len(ops))
+	var moves []Operation
+	for i, d := range ops {
+		if d.Type != OpDelete || d.Node == nil {
+			continue
+		}
+		for j, ins := range ops {
+			if consumed[j] || ins.Type != OpInsert || ins.Node == nil {
+				continue
+			}
+			if hashes[d.Node] == hashes[ins.Node] {
+				moves = append(moves, Operation{
+					Type: OpMove, Path: d.Path, NewPath: ins.Path, Node: d.Node,
+					destParent: ins.Node.Parent,
+				})
+				consumed[i] = true
+				consumed[j] = true
+				break
+			}
+		}
+	}
+	if len(moves) == 0 {
+		return ops
+	}
+
+	out := make([]Operation, 0, len(ops)+len(moves))
+	for i, op := range ops {
+		if !consumed[i] {
+			out = append(out, op)
+		}
+	}
+	return append(out, moves...)
+}
+
+func ownText(n *node.HTMLNode) string {
+	var b strings.Builder
+	for _, c := range n.Children {
+		if c.Type == "text" {
+			b.WriteString(c.TextContent)
+		}
+	}
+	return b.String()
+}
+
+func symmetricDiffRatio(a, b []string) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 0
+	}
+	set := map[string]int{}
+	for _, s := range a {
+		set[s] |= 1
+	}
+	for _, s := range b {
+		set[s] |= 2
+	}
+	diff := 0
+	for _, v := range set {
+		if v == 1 || v == 2 {
+			diff++
+		}
+	}
+	return float64(diff) / float64(len(set))
+}
+
+func attrDiffRatio(a, b map[string]string) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 0
+	}
+	keys := map[string]bool{}
+	for k := range a {
+		keys[k] = true
+	}
+	for k := range b {
+		keys[k] = true
+	}
+	diff := 0
+	for k := range keys {
+		if a[k] != b[k] {
+			diff++
+		}
+	}
+	return float64(diff) / float64(len(keys))
+}
+
+// levenshteinNormalized returns the Levenshtein edit distance between a and
+// b, normalized to [0, 1] by the longer string's length.
+func levenshteinNormalized(a, b string) float64 {
+	if a == b {
+		return 0
+	}
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 {
+		return 1
+	}
+	if len(rb) == 0 {
+		return 1
+	}
+
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min3i(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+
+	maxLen := len(ra)
+	if len(rb) > maxLen {
+		maxLen = len(rb)
+	}
+	return float64(prev[len(rb)]) / float64(maxLen)
+}
+
+func min3i(a, b, c int) int {
+	m := a
+	if b < m {
+		m = b
+	}
+	if c < m {
+		m = c
+	}
+	return m
+}
+
+// hashSubtree computes a structural hash (tag + sorted attrs + child
+// hashes) for n and every descendant, memoizing results in out.
+func hashSubtree(n *node.HTMLNode, out map[*node.HTMLNode]uint64) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(n.Type))
+	if n.Type == "text" {
+		h.Write([]byte(n.TextContent))
+	} else {
+		h.Write([]byte(n.TagName))
+		keys := make([]string, 0, len(n.Attributes))
+		for k := range n.Attributes {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			h.Write([]byte(k))
+			h.Write([]byte("="))
+			h.Write([]byte(n.Attributes[k]))
+		}
+		for _, c := range n.Children {
+			childHash := hashSubtree(c, out)
+			h.Write([]byte{
+				byte(childHash), byte(childHash >> 8), byte(childHash >> 16), byte(childHash >> 24),
+				byte(childHash >> 32), byte(childHash >> 40), byte(childHash >> 48), byte(childHash >> 56),
+			})
+		}
+	}
+	sum := h.Sum64()
+	out[n] = sum
+	return sum
+}
@@ -0,0 +1,114 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+
+	"github.com/EslamAhmed171/HTML-Parser/node"
+)
+
+func parseForTest(t *testing.T, src string) *node.HTMLNode {
+	t.Helper()
+	doc, err := html.Parse(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("html.Parse: %v", err)
+	}
+	return node.RenderNode(doc, nil, node.ParserConfig{NormalizeWhitespace: true}, "", "")
+}
+
+func listItems(n *node.HTMLNode) []*node.HTMLNode {
+	var out []*node.HTMLNode
+	var walk func(n *node.HTMLNode)
+	walk = func(n *node.HTMLNode) {
+		if n.Type == "element" && n.TagName == "li" {
+			out = append(out, n)
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(n)
+	return out
+}
+
+func textOf(n *node.HTMLNode) string {
+	var b strings.Builder
+	var walk func(n *node.HTMLNode)
+	walk = func(n *node.HTMLNode) {
+		if n.Type == "text" {
+			b.WriteString(n.TextContent)
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(n)
+	return b.String()
+}
+
+// TestApplyOnRepeatedSiblings is the maintainer's repro: two trees that
+// differ only in one <li>'s text plus an appended 4th <li>. Since every <li>
+// shares the same SelectorPath, a path-string-keyed Apply can't tell them
+// apart; this must round-trip via pointer identity instead.
+func TestApplyOnRepeatedSiblings(t *testing.T) {
+	a := parseForTest(t, `<html><body><ul><li>one</li><li>two</li><li>three</li></ul></body></html>`)
+	b := parseForTest(t, `<html><body><ul><li>one</li><li>TWO-CHANGED</li><li>three</li><li>four</li></ul></body></html>`)
+
+	patch, err := Compute(a, b, Options{})
+	if err != nil {
+		t.Fatalf("Compute: %v", err)
+	}
+
+	if _, err := Apply(a, patch); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	if a.TextContent != "" {
+		t.Errorf("document root TextContent must stay empty, got %q", a.TextContent)
+	}
+
+	items := listItems(a)
+	if len(items) != 4 {
+		t.Fatalf("want 4 <li> after Apply, got %d", len(items))
+	}
+	got := make([]string, len(items))
+	for i, li := range items {
+		got[i] = textOf(li)
+	}
+	want := []string{"one", "TWO-CHANGED", "three", "four"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("item %d: got %q, want %q (full: %v)", i, got[i], want[i], got)
+		}
+	}
+}
+
+// TestComputeSubtreeHashingMatchesIdenticalSiblings checks that, with
+// SubtreeHashing on, identical sibling subtrees are matched 1:1 and only the
+// changed <li> is updated when the patch is applied back onto tree A.
+func TestComputeSubtreeHashingMatchesIdenticalSiblings(t *testing.T) {
+	a := parseForTest(t, `<ul><li>same</li><li>same</li><li>old</li></ul>`)
+	b := parseForTest(t, `<ul><li>same</li><li>same</li><li>new</li></ul>`)
+
+	patch, err := Compute(a, b, Options{SubtreeHashing: true})
+	if err != nil {
+		t.Fatalf("Compute: %v", err)
+	}
+	if _, err := Apply(a, patch); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	items := listItems(a)
+	if len(items) != 3 {
+		t.Fatalf("want 3 <li>, got %d", len(items))
+	}
+	got := []string{textOf(items[0]), textOf(items[1]), textOf(items[2])}
+	want := []string{"same", "same", "new"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("item %d: got %q, want %q", i, got[i], want[i])
+		}
+	}
+}
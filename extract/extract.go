@@ -0,0 +1,52 @@
+// Package extract reads structured data back out of an already-parsed
+// document: JSON-LD payloads, HTML Microdata, RDFa Lite, and OpenGraph/
+// Twitter Card meta tags. It expects root to have been parsed with
+// node.ParserConfig.KeepScripts set, since JSON-LD lives inside <script>
+// elements that the default config strips.
+package extract
+
+import (
+	"fmt"
+
+	"github.com/EslamAhmed171/HTML-Parser/node"
+)
+
+// StructuredData is the union of every structured-data format Structured
+// knows how to read out of a page. Any field may be empty if the page
+// doesn't use that format.
+type StructuredData struct {
+	JSONLD    []map[string]interface{} `json:"jsonLD,omitempty"`
+	Microdata []Item                   `json:"microdata,omitempty"`
+	RDFa      []Item                   `json:"rdfa,omitempty"`
+	OpenGraph *OpenGraph               `json:"openGraph,omitempty"`
+}
+
+// Structured reads every JSON-LD, Microdata, RDFa Lite and OpenGraph/Twitter
+// Card item out of root.
+func Structured(root *node.HTMLNode) (*StructuredData, error) {
+	if root == nil {
+		return nil, fmt.Errorf("extract: root is nil")
+	}
+
+	return &StructuredData{
+		JSONLD:    extractJSONLD(root),
+		Microdata: extractMicrodata(root),
+		RDFa:      extractRDFa(root),
+		OpenGraph: extractOpenGraph(root),
+	}, nil
+}
+
+func textOf(n *node.HTMLNode) string {
+	var b []byte
+	var walk func(n *node.HTMLNode)
+	walk = func(n *node.HTMLNode) {
+		if n.Type == "text" {
+			b = append(b, n.TextContent...)
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(n)
+	return string(b)
+}
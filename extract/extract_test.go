@@ -0,0 +1,148 @@
+package extract
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+
+	"github.com/EslamAhmed171/HTML-Parser/node"
+)
+
+func parseForTest(t *testing.T, src string) *node.HTMLNode {
+	t.Helper()
+	doc, err := html.Parse(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("html.Parse: %v", err)
+	}
+	return node.RenderNode(doc, nil, node.ParserConfig{NormalizeWhitespace: true, KeepScripts: true}, "", "")
+}
+
+func TestExtractJSONLDExpandsGraph(t *testing.T) {
+	const src = `<html><body><script type="application/ld+json">
+		{"@context": "https://schema.org", "@graph": [
+			{"@type": "Person", "name": "Ada"},
+			{"@type": "Organization", "name": "Acme"}
+		]}
+	</script></body></html>`
+
+	root := parseForTest(t, src)
+	got := extractJSONLD(root)
+
+	if len(got) != 2 {
+		t.Fatalf("extractJSONLD returned %d entries, want 2: %v", len(got), got)
+	}
+	if got[0]["@type"] != "Person" || got[0]["name"] != "Ada" {
+		t.Errorf("entry 0 = %v, want @type Person, name Ada", got[0])
+	}
+	if got[0]["@context"] != "https://schema.org" {
+		t.Errorf("entry 0 @context = %v, want graph's @context inherited", got[0]["@context"])
+	}
+	if got[1]["@type"] != "Organization" || got[1]["name"] != "Acme" {
+		t.Errorf("entry 1 = %v, want @type Organization, name Acme", got[1])
+	}
+}
+
+func TestExtractMicrodataNestedItemrefAndItemscope(t *testing.T) {
+	const src = `<html><body>
+		<div itemscope itemtype="https://schema.org/Product" itemref="warranty">
+			<span itemprop="name">Widget</span>
+			<div itemprop="brand" itemscope itemtype="https://schema.org/Brand">
+				<span itemprop="name">Acme</span>
+			</div>
+		</div>
+		<p id="warranty" itemprop="description">Two year warranty</p>
+	</body></html>`
+
+	root := parseForTest(t, src)
+	items := extractMicrodata(root)
+
+	if len(items) != 1 {
+		t.Fatalf("extractMicrodata returned %d top-level items, want 1: %v", len(items), items)
+	}
+	product := items[0]
+	if product.Type != "https://schema.org/Product" {
+		t.Errorf("product.Type = %q", product.Type)
+	}
+	if product.Properties["name"] != "Widget" {
+		t.Errorf("product name = %v, want Widget", product.Properties["name"])
+	}
+	if product.Properties["description"] != "Two year warranty" {
+		t.Errorf("itemref'd description = %v, want \"Two year warranty\"", product.Properties["description"])
+	}
+	brand, ok := product.Properties["brand"].(Item)
+	if !ok {
+		t.Fatalf("brand property = %#v, want nested Item", product.Properties["brand"])
+	}
+	if brand.Properties["name"] != "Acme" {
+		t.Errorf("brand name = %v, want Acme", brand.Properties["name"])
+	}
+}
+
+func TestExtractRDFaExpandsVocabCURIEs(t *testing.T) {
+	const src = `<html><body>
+		<div vocab="https://schema.org/" typeof="Person" resource="#ada">
+			<span property="name">Ada</span>
+			<span property="foaf:knows">Babbage</span>
+		</div>
+	</body></html>`
+
+	root := parseForTest(t, src)
+	items := extractRDFa(root)
+
+	if len(items) != 1 {
+		t.Fatalf("extractRDFa returned %d items, want 1: %v", len(items), items)
+	}
+	person := items[0]
+	if person.Type != "https://schema.org/Person" {
+		t.Errorf("person.Type = %q, want vocab-expanded Person", person.Type)
+	}
+	if person.ID != "#ada" {
+		t.Errorf("person.ID = %q, want #ada", person.ID)
+	}
+	if person.Properties["https://schema.org/name"] != "Ada" {
+		t.Errorf("name property = %v, want vocab-expanded key with value Ada", person.Properties["https://schema.org/name"])
+	}
+	if person.Properties["foaf:knows"] != "Babbage" {
+		t.Errorf("foaf:knows property = %v, want unchanged CURIE key", person.Properties["foaf:knows"])
+	}
+}
+
+func TestExtractOpenGraphFlattensOgAndTwitter(t *testing.T) {
+	const src = `<html><head>
+		<meta property="og:title" content="Cool Article">
+		<meta property="og:type" content="article">
+		<meta property="og:site_name" content="Example">
+		<meta property="og:audio" content="https://example.com/a.mp3">
+		<meta name="twitter:card" content="summary">
+	</head><body></body></html>`
+
+	root := parseForTest(t, src)
+	og := extractOpenGraph(root)
+
+	if og == nil {
+		t.Fatal("extractOpenGraph returned nil, want a populated OpenGraph")
+	}
+	if og.Title != "Cool Article" {
+		t.Errorf("Title = %q", og.Title)
+	}
+	if og.Type != "article" {
+		t.Errorf("Type = %q", og.Type)
+	}
+	if og.SiteName != "Example" {
+		t.Errorf("SiteName = %q", og.SiteName)
+	}
+	if og.Extra["audio"] != "https://example.com/a.mp3" {
+		t.Errorf("Extra[audio] = %q, want uncommon og:* property to land in Extra", og.Extra["audio"])
+	}
+	if og.Twitter["card"] != "summary" {
+		t.Errorf("Twitter[card] = %q", og.Twitter["card"])
+	}
+}
+
+func TestExtractOpenGraphReturnsNilWhenAbsent(t *testing.T) {
+	root := parseForTest(t, `<html><head><title>No OG here</title></head><body></body></html>`)
+	if og := extractOpenGraph(root); og != nil {
+		t.Errorf("extractOpenGraph = %+v, want nil", og)
+	}
+}
@@ -0,0 +1,87 @@
+package extract
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/EslamAhmed171/HTML-Parser/node"
+)
+
+// extractJSONLD collects every <script type="application/ld+json"> payload
+// under root, parsing each into a generic map. A script containing a
+// top-level JSON array, or an object using the @graph convention, is
+// flattened into one entry per item.
+func extractJSONLD(root *node.HTMLNode) []map[string]interface{} {
+	var out []map[string]interface{}
+	var walk func(n *node.HTMLNode)
+	walk = func(n *node.HTMLNode) {
+		if n.Type == "element" && n.TagName == "script" && strings.EqualFold(n.Attributes["type"], "application/ld+json") {
+			out = append(out, parseJSONLD(scriptText(n))...)
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(root)
+	return out
+}
+
+func scriptText(n *node.HTMLNode) string {
+	var b strings.Builder
+	for _, c := range n.Children {
+		if c.Type == "text" {
+			b.WriteString(c.TextContent)
+		}
+	}
+	return b.String()
+}
+
+func parseJSONLD(raw string) []map[string]interface{} {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil
+	}
+
+	var asObject map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &asObject); err == nil {
+		return expandGraph(asObject)
+	}
+
+	var asArray []map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &asArray); err == nil {
+		var out []map[string]interface{}
+		for _, obj := range asArray {
+			out = append(out, expandGraph(obj)...)
+		}
+		return out
+	}
+
+	return nil
+}
+
+// expandGraph resolves @context onto each node of a schema.org @graph bundle
+// and returns one entry per graph node instead of the wrapper object; objects
+// that don't use @graph are returned as a single-element slice unchanged.
+func expandGraph(obj map[string]interface{}) []map[string]interface{} {
+	context, hasContext := obj["@context"]
+
+	graph, ok := obj["@graph"].([]interface{})
+	if !ok {
+		return []map[string]interface{}{obj}
+	}
+
+	var out []map[string]interface{}
+	for _, item := range graph {
+		n, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if hasContext {
+			if _, already := n["@context"]; !already {
+				n["@context"] = context
+			}
+		}
+		out = append(out, n)
+	}
+	return out
+}
@@ -0,0 +1,159 @@
+package extract
+
+import (
+	"strings"
+
+	"github.com/EslamAhmed171/HTML-Parser/node"
+)
+
+// Item is a single microdata or RDFa item: a typed bag of properties, each
+// of which may be a nested Item, a string, or (when a name repeats) a
+// []interface{} of either.
+type Item struct {
+	Type       string                 `json:"type,omitempty"`
+	ID         string                 `json:"id,omitempty"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+// extractMicrodata finds every top-level microdata item under root: elements
+// carrying itemscope but not themselves the value of another item's
+// itemprop (those are nested items, discovered while walking their owner).
+func extractMicrodata(root *node.HTMLNode) []Item {
+	ids := indexByID(root)
+
+	var items []Item
+	var walk func(n *node.HTMLNode)
+	walk = func(n *node.HTMLNode) {
+		if n.Type == "element" {
+			if _, isScope := n.Attributes["itemscope"]; isScope {
+				if _, isProp := n.Attributes["itemprop"]; !isProp {
+					items = append(items, buildMicrodataItem(n, ids, map[*node.HTMLNode]bool{}))
+				}
+			}
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(root)
+	return items
+}
+
+func buildMicrodataItem(scope *node.HTMLNode, ids map[string]*node.HTMLNode, seen map[*node.HTMLNode]bool) Item {
+	if seen[scope] {
+		return Item{Type: scope.Attributes["itemtype"]}
+	}
+	seen[scope] = true
+
+	item := Item{Type: scope.Attributes["itemtype"], ID: scope.Attributes["itemid"]}
+	props := map[string]interface{}{}
+
+	roots := elementChildren(scope)
+	if ref := scope.Attributes["itemref"]; ref != "" {
+		for _, id := range strings.Fields(ref) {
+			if target := ids[id]; target != nil {
+				roots = append(roots, target)
+			}
+		}
+	}
+
+	for _, r := range roots {
+		collectMicrodataProps(r, ids, seen, props)
+	}
+
+	item.Properties = props
+	return item
+}
+
+// collectMicrodataProps walks n looking for itemprop-bearing descendants,
+// stopping at nested itemscope boundaries: a nested item's own properties
+// are built separately by buildMicrodataItem, and an itemscope element that
+// isn't itself an itemprop value belongs to its own top-level item instead.
+func collectMicrodataProps(n *node.HTMLNode, ids map[string]*node.HTMLNode, seen map[*node.HTMLNode]bool, props map[string]interface{}) {
+	if n.Type != "element" {
+		return
+	}
+
+	if prop, ok := n.Attributes["itemprop"]; ok {
+		var value interface{}
+		_, nested := n.Attributes["itemscope"]
+		if nested {
+			value = buildMicrodataItem(n, ids, seen)
+		} else {
+			value = microdataValue(n)
+		}
+		for _, name := range strings.Fields(prop) {
+			addProp(props, name, value)
+		}
+		if nested {
+			return
+		}
+	} else if _, isScope := n.Attributes["itemscope"]; isScope {
+		return
+	}
+
+	for _, c := range n.Children {
+		collectMicrodataProps(c, ids, seen, props)
+	}
+}
+
+// microdataValue implements the relevant part of the HTML microdata value
+// algorithm: URL-bearing elements contribute their URL attribute, <meta>
+// contributes its content, <time> its datetime, everything else its text.
+func microdataValue(n *node.HTMLNode) string {
+	switch n.TagName {
+	case "meta":
+		return n.Attributes["content"]
+	case "a", "area", "link":
+		return n.Attributes["href"]
+	case "img", "audio", "video", "source", "iframe", "embed", "track":
+		return n.Attributes["src"]
+	case "object":
+		return n.Attributes["data"]
+	case "time":
+		if dt, ok := n.Attributes["datetime"]; ok {
+			return dt
+		}
+		return strings.TrimSpace(textOf(n))
+	default:
+		return strings.TrimSpace(textOf(n))
+	}
+}
+
+func addProp(props map[string]interface{}, name string, value interface{}) {
+	existing, ok := props[name]
+	if !ok {
+		props[name] = value
+		return
+	}
+	if list, ok := existing.([]interface{}); ok {
+		props[name] = append(list, value)
+		return
+	}
+	props[name] = []interface{}{existing, value}
+}
+
+func elementChildren(n *node.HTMLNode) []*node.HTMLNode {
+	var out []*node.HTMLNode
+	for _, c := range n.Children {
+		if c.Type == "element" {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func indexByID(root *node.HTMLNode) map[string]*node.HTMLNode {
+	out := map[string]*node.HTMLNode{}
+	var walk func(n *node.HTMLNode)
+	walk = func(n *node.HTMLNode) {
+		if n.Type == "element" && n.ID != "" {
+			out[n.ID] = n
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(root)
+	return out
+}
@@ -0,0 +1,79 @@
+package extract
+
+import (
+	"strings"
+
+	"github.com/EslamAhmed171/HTML-Parser/node"
+)
+
+// OpenGraph holds the most commonly consumed og:* properties plus any
+// twitter:* card tags; less common og:* properties collect into Extra so
+// callers aren't limited to the handful of named fields.
+type OpenGraph struct {
+	Title       string            `json:"title,omitempty"`
+	Type        string            `json:"type,omitempty"`
+	Image       string            `json:"image,omitempty"`
+	URL         string            `json:"url,omitempty"`
+	Description string            `json:"description,omitempty"`
+	SiteName    string            `json:"siteName,omitempty"`
+	Twitter     map[string]string `json:"twitter,omitempty"`
+	Extra       map[string]string `json:"extra,omitempty"`
+}
+
+// extractOpenGraph flattens <meta property="og:*"> and
+// <meta name="twitter:*"> tags under root. It returns nil if root has
+// neither.
+func extractOpenGraph(root *node.HTMLNode) *OpenGraph {
+	og := &OpenGraph{}
+	found := false
+
+	var walk func(n *node.HTMLNode)
+	walk = func(n *node.HTMLNode) {
+		if n.Type == "element" && n.TagName == "meta" {
+			content := n.Attributes["content"]
+			if key := n.Attributes["property"]; strings.HasPrefix(key, "og:") {
+				found = true
+				applyOpenGraphField(og, strings.TrimPrefix(key, "og:"), content)
+			} else if key := n.Attributes["name"]; strings.HasPrefix(key, "twitter:") {
+				found = true
+				if og.Twitter == nil {
+					og.Twitter = map[string]string{}
+				}
+				og.Twitter[strings.TrimPrefix(key, "twitter:")] = content
+			}
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(root)
+
+	if !found {
+		return nil
+	}
+	return og
+}
+
+func applyOpenGraphField(og *OpenGraph, key, value string) {
+	switch key {
+	case "title":
+		og.Title = value
+	case "type":
+		og.Type = value
+	case "image", "image:url", "image:secure_url":
+		if og.Image == "" {
+			og.Image = value
+		}
+	case "url":
+		og.URL = value
+	case "description":
+		og.Description = value
+	case "site_name":
+		og.SiteName = value
+	default:
+		if og.Extra == nil {
+			og.Extra = map[string]string{}
+		}
+		og.Extra[key] = value
+	}
+}
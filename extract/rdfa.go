@@ -0,0 +1,105 @@
+package extract
+
+import (
+	"strings"
+
+	"github.com/EslamAhmed171/HTML-Parser/node"
+)
+
+// extractRDFa finds every top-level RDFa Lite item under root: elements
+// carrying typeof, expanding bare type/property terms against the nearest
+// ancestor's vocab attribute per the RDFa Lite 1.1 vocabulary-expansion rule.
+func extractRDFa(root *node.HTMLNode) []Item {
+	var items []Item
+	var walk func(n *node.HTMLNode, vocab string)
+	walk = func(n *node.HTMLNode, vocab string) {
+		if n.Type == "element" {
+			if v, ok := n.Attributes["vocab"]; ok {
+				vocab = v
+			}
+			if _, ok := n.Attributes["typeof"]; ok {
+				items = append(items, buildRDFaItem(n, vocab))
+				return // a nested typeof element is read only as the value of its own property attribute; one without property is not extracted
+			}
+		}
+		for _, c := range n.Children {
+			walk(c, vocab)
+		}
+	}
+	walk(root, "")
+	return items
+}
+
+func buildRDFaItem(n *node.HTMLNode, vocab string) Item {
+	id := n.Attributes["resource"]
+	if id == "" {
+		id = n.Attributes["about"]
+	}
+	item := Item{Type: expandCURIE(n.Attributes["typeof"], vocab), ID: id}
+
+	props := map[string]interface{}{}
+	for _, c := range n.Children {
+		collectRDFaProps(c, vocab, props)
+	}
+	item.Properties = props
+	return item
+}
+
+func collectRDFaProps(n *node.HTMLNode, vocab string, props map[string]interface{}) {
+	if n.Type != "element" {
+		return
+	}
+	if v, ok := n.Attributes["vocab"]; ok {
+		vocab = v
+	}
+
+	if prop, ok := n.Attributes["property"]; ok {
+		var value interface{}
+		_, nested := n.Attributes["typeof"]
+		if nested {
+			value = buildRDFaItem(n, vocab)
+		} else {
+			value = rdfaValue(n)
+		}
+		for _, name := range strings.Fields(prop) {
+			addProp(props, expandCURIE(name, vocab), value)
+		}
+		if nested {
+			return
+		}
+	} else if _, isScope := n.Attributes["typeof"]; isScope {
+		return
+	}
+
+	for _, c := range n.Children {
+		collectRDFaProps(c, vocab, props)
+	}
+}
+
+func rdfaValue(n *node.HTMLNode) string {
+	if content, ok := n.Attributes["content"]; ok {
+		return content
+	}
+	switch n.TagName {
+	case "a", "link":
+		return n.Attributes["href"]
+	case "img":
+		return n.Attributes["src"]
+	case "time":
+		if dt, ok := n.Attributes["datetime"]; ok {
+			return dt
+		}
+	}
+	return strings.TrimSpace(textOf(n))
+}
+
+// expandCURIE resolves a bare term (no scheme, no prefix) against vocab.
+// Terms that already look like a full URI or a prefixed CURIE (foaf:name)
+// are returned unchanged.
+func expandCURIE(term, vocab string) string {
+	term = strings.TrimSpace(term)
+	if term == "" || vocab == "" || strings.Contains(term, "://") || strings.Contains(term, ":") {
+		return term
+	}
+	return vocab + term
+}
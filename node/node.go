@@ -0,0 +1,382 @@
+// Package node defines the simplified HTML node structure used throughout
+// this module and the logic for building it from golang.org/x/net/html
+// documents. It is kept free of any CLI or selector-matching concerns so
+// that other packages (selector, dom, diff, readability, extract, crawl)
+// can depend on it without pulling in the rest of the program.
+package node
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// HTMLNode represents a simplified HTML node structure for comparison
+type HTMLNode struct {
+	Type         string            `json:"type"`
+	TagName      string            `json:"tagName,omitempty"`
+	Attributes   map[string]string `json:"attributes,omitempty"`
+	TextContent  string            `json:"textContent,omitempty"`
+	Children     []*HTMLNode       `json:"children,omitempty"`
+	ClassList    []string          `json:"classList,omitempty"`
+	ID           string            `json:"id,omitempty"`
+	ComputedPath string            `json:"computedPath,omitempty"`
+	SelectorPath string            `json:"selectorPath,omitempty"`
+
+	// Parent points back to the enclosing element so that combinators like
+	// ">", "+", "~" and pseudo-classes like :nth-child can walk upward.
+	// It is excluded from JSON output since it would otherwise create a
+	// reference cycle.
+	Parent *HTMLNode `json:"-"`
+}
+
+// ParserConfig holds configuration for the HTML parser
+type ParserConfig struct {
+	NormalizeWhitespace bool
+
+	// KeepScripts renders <script> elements instead of skipping them, with
+	// their raw (unnormalized) text content intact. Off by default since
+	// most callers want script/style noise stripped; the extract package
+	// turns it on to reach <script type="application/ld+json"> payloads.
+	KeepScripts bool
+
+	// KeepDataAttributes disables stripping of data-*/aria-* attributes.
+	KeepDataAttributes bool
+}
+
+// RenderNode converts an html.Node to our HTMLNode structure, linking each
+// produced node back to its parent.
+func RenderNode(n *html.Node, parent *HTMLNode, config ParserConfig, path string, selectorPath string) *HTMLNode {
+	// Skip script and style nodes, unless the caller asked to keep scripts
+	// around (the extract package does this to reach JSON-LD payloads).
+	if ShouldSkipNode(n) && !(config.KeepScripts && isScript(n)) {
+		return nil
+	}
+
+	result := &HTMLNode{Parent: parent}
+
+	// Set node type
+	switch n.Type {
+	case html.ElementNode:
+		result.Type = "element"
+		result.TagName = n.DataAtom.String()
+		if result.TagName == "" {
+			// Custom elements will have empty DataAtom but have Data
+			result.TagName = strings.ToLower(n.Data)
+		}
+	case html.TextNode:
+		result.Type = "text"
+		text := n.Data
+		// Script contents are never whitespace-normalized, config setting
+		// notwithstanding: collapsing runs of whitespace inside embedded
+		// JSON (JSON-LD, config blobs) would corrupt it.
+		if config.NormalizeWhitespace && !(parent != nil && parent.TagName == "script") {
+			text = normalizeText(text)
+		}
+		if text == "" {
+			return nil // Skip empty text nodes
+		}
+		result.TextContent = text
+		return result // Text nodes don't have children or attributes
+	case html.CommentNode:
+		// Skip comments
+		return nil
+	case html.DocumentNode:
+		result.Type = "document"
+	default:
+		// Skip other node types like doctypes
+		return nil
+	}
+
+	// Process element attributes
+	if n.Type == html.ElementNode {
+		result.Attributes = make(map[string]string)
+		for _, attr := range n.Attr {
+			if !config.KeepDataAttributes && ShouldSkipAttribute(attr.Key) {
+				continue
+			}
+			result.Attributes[attr.Key] = attr.Val
+
+			// Track ID and classes separately for easy comparison
+			if attr.Key == "id" {
+				result.ID = attr.Val
+			} else if attr.Key == "class" {
+				result.ClassList = strings.Fields(attr.Val)
+			}
+		}
+	}
+
+	// Update path for this node
+	nodePath := path
+	if n.Type == html.ElementNode {
+		if path == "" {
+			nodePath = n.DataAtom.String()
+		} else {
+			nodePath = path + " > " + n.DataAtom.String()
+		}
+
+		// Create a CSS selector-like path
+		nodeSelector := n.DataAtom.String()
+		if result.ID != "" {
+			nodeSelector += "#" + result.ID
+		} else if len(result.ClassList) > 0 {
+			nodeSelector += "." + strings.Join(result.ClassList, ".")
+		}
+
+		if selectorPath == "" {
+			result.SelectorPath = nodeSelector
+		} else {
+			result.SelectorPath = selectorPath + " > " + nodeSelector
+		}
+	}
+
+	result.ComputedPath = nodePath
+
+	// Process children
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		childNode := RenderNode(c, result, config, nodePath, result.SelectorPath)
+		if childNode != nil {
+			if result.Children == nil {
+				result.Children = make([]*HTMLNode, 0)
+			}
+			result.Children = append(result.Children, childNode)
+		}
+	}
+
+	return result
+}
+
+// RecomputePaths recalculates ComputedPath and SelectorPath for n and all of
+// its descendants based on n's current Parent chain. Callers that move or
+// reparent a subtree (see pkg/dom) must call this afterwards so the paths
+// stay consistent with the new position in the tree.
+func RecomputePaths(n *HTMLNode) {
+	path, selectorPath := "", ""
+	if n.Parent != nil {
+		path, selectorPath = n.Parent.ComputedPath, n.Parent.SelectorPath
+	}
+	recomputePaths(n, path, selectorPath)
+}
+
+func recomputePaths(n *HTMLNode, path, selectorPath string) {
+	if n.Type == "element" {
+		if path == "" {
+			path = n.TagName
+		} else {
+			path = path + " > " + n.TagName
+		}
+
+		sel := n.TagName
+		if n.ID != "" {
+			sel += "#" + n.ID
+		} else if len(n.ClassList) > 0 {
+			sel += "." + strings.Join(n.ClassList, ".")
+		}
+		if selectorPath == "" {
+			selectorPath = sel
+		} else {
+			selectorPath = selectorPath + " > " + sel
+		}
+	}
+
+	n.ComputedPath = path
+	n.SelectorPath = selectorPath
+
+	for _, c := range n.Children {
+		recomputePaths(c, path, selectorPath)
+	}
+}
+
+// ShouldSkipNode checks if a node should be skipped
+func ShouldSkipNode(n *html.Node) bool {
+	if n.Type == html.ElementNode {
+		tagName := strings.ToLower(n.DataAtom.String())
+		if tagName == "" {
+			tagName = strings.ToLower(n.Data)
+		}
+		return tagName == "script" || tagName == "style"
+	}
+	return false
+}
+
+// isScript reports whether n is a <script> element, the one skip-eligible
+// tag that KeepScripts is allowed to override (<style> stays skipped).
+func isScript(n *html.Node) bool {
+	if n.Type != html.ElementNode {
+		return false
+	}
+	tagName := strings.ToLower(n.DataAtom.String())
+	if tagName == "" {
+		tagName = strings.ToLower(n.Data)
+	}
+	return tagName == "script"
+}
+
+// ShouldSkipAttribute checks if an attribute should be skipped
+func ShouldSkipAttribute(attrName string) bool {
+	attrName = strings.ToLower(attrName)
+	return strings.HasPrefix(attrName, "data-") || strings.HasPrefix(attrName, "aria-")
+}
+
+// normalizeText removes extra whitespace from text
+func normalizeText(s string) string {
+	// Replace all whitespace sequences with a single space
+	s = strings.Join(strings.Fields(s), " ")
+	return strings.TrimSpace(s)
+}
+
+// NormalizeText is the exported form of normalizeText, for callers (such as
+// the stream package) that build nodes without going through RenderNode.
+func NormalizeText(s string) string {
+	return normalizeText(s)
+}
+
+// PrintTree prints the HTML structure as a tree
+func PrintTree(w interface{ Write([]byte) (int, error) }, n *HTMLNode, level int) {
+	indent := strings.Repeat("  ", level)
+
+	switch n.Type {
+	case "element":
+		fmt.Fprintf(w, "%s<%s", indent, n.TagName)
+
+		// Print attributes
+		if n.ID != "" {
+			fmt.Fprintf(w, " id=\"%s\"", n.ID)
+		}
+		if len(n.ClassList) > 0 {
+			fmt.Fprintf(w, " class=\"%s\"", strings.Join(n.ClassList, " "))
+		}
+
+		for k, v := range n.Attributes {
+			if k != "id" && k != "class" {
+				fmt.Fprintf(w, " %s=\"%s\"", k, v)
+			}
+		}
+
+		if len(n.Children) == 0 {
+			fmt.Fprintln(w, "/>")
+		} else {
+			fmt.Fprintln(w, ">")
+
+			// Print children
+			for _, child := range n.Children {
+				PrintTree(w, child, level+1)
+			}
+
+			fmt.Fprintf(w, "%s</%s>\n", indent, n.TagName)
+		}
+	case "text":
+		fmt.Fprintf(w, "%s\"%s\"\n", indent, n.TextContent)
+	case "document":
+		for _, child := range n.Children {
+			PrintTree(w, child, level)
+		}
+	}
+}
+
+// CompareHTMLNodes compares two HTML node structures
+func CompareHTMLNodes(a, b *HTMLNode) (bool, []string) {
+	differences := []string{}
+
+	// Check node type
+	if a.Type != b.Type {
+		differences = append(differences, fmt.Sprintf("Node type mismatch: %s vs %s at %s",
+			a.Type, b.Type, a.ComputedPath))
+		return false, differences
+	}
+
+	// For text nodes, compare content
+	if a.Type == "text" {
+		if a.TextContent != b.TextContent {
+			differences = append(differences, fmt.Sprintf("Text content mismatch at %s: \"%s\" vs \"%s\"",
+				a.ComputedPath, a.TextContent, b.TextContent))
+		}
+		return len(differences) == 0, differences
+	}
+
+	// For element nodes, compare tag name and attributes
+	if a.Type == "element" {
+		if a.TagName != b.TagName {
+			differences = append(differences, fmt.Sprintf("Tag name mismatch at %s: %s vs %s",
+				a.ComputedPath, a.TagName, b.TagName))
+		}
+
+		// Compare IDs
+		if a.ID != b.ID {
+			differences = append(differences, fmt.Sprintf("ID mismatch at %s: %s vs %s",
+				a.ComputedPath, a.ID, b.ID))
+		}
+
+		// Compare classes (order-independent)
+		if !equalStringSlices(a.ClassList, b.ClassList) {
+			differences = append(differences, fmt.Sprintf("Class list mismatch at %s: %v vs %v",
+				a.ComputedPath, a.ClassList, b.ClassList))
+		}
+
+		// Compare attributes
+		for k, v := range a.Attributes {
+			if k != "id" && k != "class" {
+				if bv, ok := b.Attributes[k]; !ok {
+					differences = append(differences, fmt.Sprintf("Missing attribute %s at %s",
+						k, a.ComputedPath))
+				} else if v != bv {
+					differences = append(differences, fmt.Sprintf("Attribute %s mismatch at %s: %s vs %s",
+						k, a.ComputedPath, v, bv))
+				}
+			}
+		}
+
+		for k := range b.Attributes {
+			if k != "id" && k != "class" {
+				if _, ok := a.Attributes[k]; !ok {
+					differences = append(differences, fmt.Sprintf("Extra attribute %s at %s",
+						k, b.ComputedPath))
+				}
+			}
+		}
+
+		// Compare children
+		if len(a.Children) != len(b.Children) {
+			differences = append(differences, fmt.Sprintf("Children count mismatch at %s: %d vs %d",
+				a.ComputedPath, len(a.Children), len(b.Children)))
+		} else {
+			for i := range a.Children {
+				if i < len(b.Children) {
+					equal, childDiffs := CompareHTMLNodes(a.Children[i], b.Children[i])
+					if !equal {
+						differences = append(differences, childDiffs...)
+					}
+				}
+			}
+		}
+	}
+
+	return len(differences) == 0, differences
+}
+
+// equalStringSlices compares two string slices, ignoring order
+func equalStringSlices(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	countA := make(map[string]int)
+	countB := make(map[string]int)
+
+	for _, s := range a {
+		countA[s]++
+	}
+
+	for _, s := range b {
+		countB[s]++
+	}
+
+	for k, v := range countA {
+		if countB[k] != v {
+			return false
+		}
+	}
+
+	return true
+}
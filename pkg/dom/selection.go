@@ -0,0 +1,567 @@
+// Package dom provides a fluent, goquery-style traversal and manipulation
+// API over *node.HTMLNode trees, built on top of the selector package.
+package dom
+
+import (
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/EslamAhmed171/HTML-Parser/node"
+	"github.com/EslamAhmed171/HTML-Parser/selector"
+)
+
+// Selection wraps a set of matched nodes and supports jQuery-style chaining.
+type Selection struct {
+	nodes []*node.HTMLNode
+}
+
+// NewSelection wraps an explicit set of nodes.
+func NewSelection(nodes ...*node.HTMLNode) *Selection {
+	return &Selection{nodes: nodes}
+}
+
+// FromRoot compiles sel and matches it against root, returning the result
+// as a Selection. This is the usual entry point, analogous to goquery's
+// NewDocumentFromNode(root).Find(sel).
+func FromRoot(root *node.HTMLNode, sel string) (*Selection, error) {
+	compiled, err := selector.Compile(sel)
+	if err != nil {
+		return nil, err
+	}
+	return &Selection{nodes: compiled.MatchAll(root)}, nil
+}
+
+// Length returns the number of nodes in the selection.
+func (s *Selection) Length() int { return len(s.nodes) }
+
+// Nodes returns the underlying matched nodes.
+func (s *Selection) Nodes() []*node.HTMLNode { return s.nodes }
+
+// Find returns the descendants of each node in s matching sel.
+func (s *Selection) Find(sel string) *Selection {
+	compiled, err := selector.Compile(sel)
+	if err != nil {
+		return &Selection{}
+	}
+	var out []*node.HTMLNode
+	for _, n := range s.nodes {
+		for _, c := range n.Children {
+			out = append(out, compiled.MatchAll(c)...)
+		}
+	}
+	return &Selection{nodes: dedupe(out)}
+}
+
+// Filter keeps only the nodes in s matching sel.
+func (s *Selection) Filter(sel string) *Selection {
+	compiled, err := selector.Compile(sel)
+	if err != nil {
+		return &Selection{}
+	}
+	return &Selection{nodes: compiled.Filter(s.nodes)}
+}
+
+// Not removes nodes matching sel from the selection.
+func (s *Selection) Not(sel string) *Selection {
+	compiled, err := selector.Compile(sel)
+	if err != nil {
+		return s
+	}
+	var out []*node.HTMLNode
+	for _, n := range s.nodes {
+		if !compiled.Match(n) {
+			out = append(out, n)
+		}
+	}
+	return &Selection{nodes: out}
+}
+
+// Parent returns the unique immediate parents of each node in s.
+func (s *Selection) Parent() *Selection {
+	var out []*node.HTMLNode
+	for _, n := range s.nodes {
+		if n.Parent != nil {
+			out = append(out, n.Parent)
+		}
+	}
+	return &Selection{nodes: dedupe(out)}
+}
+
+// Parents returns all ancestors of each node in s, nearest first.
+func (s *Selection) Parents() *Selection {
+	var out []*node.HTMLNode
+	for _, n := range s.nodes {
+		for p := n.Parent; p != nil; p = p.Parent {
+			out = append(out, p)
+		}
+	}
+	return &Selection{nodes: dedupe(out)}
+}
+
+// ParentsUntil returns the ancestors of each node in s up to (but not
+// including) the nearest ancestor matching sel.
+func (s *Selection) ParentsUntil(sel string) *Selection {
+	compiled, err := selector.Compile(sel)
+	if err != nil {
+		return s.Parents()
+	}
+	var out []*node.HTMLNode
+	for _, n := range s.nodes {
+		for p := n.Parent; p != nil; p = p.Parent {
+			if compiled.Match(p) {
+				break
+			}
+			out = append(out, p)
+		}
+	}
+	return &Selection{nodes: dedupe(out)}
+}
+
+// Children returns the direct element children of each node in s.
+func (s *Selection) Children() *Selection {
+	var out []*node.HTMLNode
+	for _, n := range s.nodes {
+		for _, c := range n.Children {
+			if c.Type == "element" {
+				out = append(out, c)
+			}
+		}
+	}
+	return &Selection{nodes: dedupe(out)}
+}
+
+// Siblings returns the other element children of each node's parent.
+func (s *Selection) Siblings() *Selection {
+	var out []*node.HTMLNode
+	for _, n := range s.nodes {
+		if n.Parent == nil {
+			continue
+		}
+		for _, c := range n.Parent.Children {
+			if c != n && c.Type == "element" {
+				out = append(out, c)
+			}
+		}
+	}
+	return &Selection{nodes: dedupe(out)}
+}
+
+// Next returns the next element sibling of each node in s, if any.
+func (s *Selection) Next() *Selection {
+	var out []*node.HTMLNode
+	for _, n := range s.nodes {
+		if sib := elementSiblingAfter(n); sib != nil {
+			out = append(out, sib)
+		}
+	}
+	return &Selection{nodes: out}
+}
+
+// Prev returns the previous element sibling of each node in s, if any.
+func (s *Selection) Prev() *Selection {
+	var out []*node.HTMLNode
+	for _, n := range s.nodes {
+		if sib := elementSiblingBefore(n); sib != nil {
+			out = append(out, sib)
+		}
+	}
+	return &Selection{nodes: out}
+}
+
+// Closest returns, for each node in s, the nearest node in itself or its
+// ancestor chain that matches sel.
+func (s *Selection) Closest(sel string) *Selection {
+	compiled, err := selector.Compile(sel)
+	if err != nil {
+		return &Selection{}
+	}
+	var out []*node.HTMLNode
+	for _, n := range s.nodes {
+		for cur := n; cur != nil; cur = cur.Parent {
+			if compiled.Match(cur) {
+				out = append(out, cur)
+				break
+			}
+		}
+	}
+	return &Selection{nodes: dedupe(out)}
+}
+
+// Each calls fn once per node in the selection.
+func (s *Selection) Each(fn func(i int, sel *Selection)) *Selection {
+	for i, n := range s.nodes {
+		fn(i, &Selection{nodes: []*node.HTMLNode{n}})
+	}
+	return s
+}
+
+// Map builds a slice by calling fn once per node in the selection.
+func (s *Selection) Map(fn func(i int, sel *Selection) string) []string {
+	out := make([]string, len(s.nodes))
+	for i, n := range s.nodes {
+		out[i] = fn(i, &Selection{nodes: []*node.HTMLNode{n}})
+	}
+	return out
+}
+
+// First returns a selection containing only the first node.
+func (s *Selection) First() *Selection { return s.Eq(0) }
+
+// Last returns a selection containing only the last node.
+func (s *Selection) Last() *Selection { return s.Eq(len(s.nodes) - 1) }
+
+// Eq returns a selection containing only the node at index i.
+func (s *Selection) Eq(i int) *Selection {
+	if i < 0 || i >= len(s.nodes) {
+		return &Selection{}
+	}
+	return &Selection{nodes: []*node.HTMLNode{s.nodes[i]}}
+}
+
+// Text returns the concatenated text content of the matched nodes and their
+// descendants.
+func (s *Selection) Text() string {
+	var b strings.Builder
+	for _, n := range s.nodes {
+		collectText(n, &b)
+	}
+	return b.String()
+}
+
+func collectText(n *node.HTMLNode, b *strings.Builder) {
+	if n.Type == "text" {
+		b.WriteString(n.TextContent)
+		return
+	}
+	for _, c := range n.Children {
+		collectText(c, b)
+	}
+}
+
+// Html returns the rendered inner HTML of the first matched node.
+func (s *Selection) Html() (string, error) {
+	if len(s.nodes) == 0 {
+		return "", nil
+	}
+	var b strings.Builder
+	for _, c := range s.nodes[0].Children {
+		if err := html.Render(&b, toHTMLNode(c)); err != nil {
+			return "", err
+		}
+	}
+	return b.String(), nil
+}
+
+// Render serializes every matched node back to HTML.
+func (s *Selection) Render(w io.Writer) error {
+	for _, n := range s.nodes {
+		if err := html.Render(w, toHTMLNode(n)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Attr returns the value of attribute key on the first node, and whether it was present.
+func (s *Selection) Attr(key string) (string, bool) {
+	if len(s.nodes) == 0 {
+		return "", false
+	}
+	v, ok := s.nodes[0].Attributes[key]
+	return v, ok
+}
+
+// SetAttr sets attribute key to value on every matched node.
+func (s *Selection) SetAttr(key, value string) *Selection {
+	for _, n := range s.nodes {
+		setAttr(n, key, value)
+	}
+	return s
+}
+
+// RemoveAttr removes attribute key from every matched node.
+func (s *Selection) RemoveAttr(key string) *Selection {
+	for _, n := range s.nodes {
+		delete(n.Attributes, key)
+		switch key {
+		case "id":
+			n.ID = ""
+		case "class":
+			n.ClassList = nil
+		}
+	}
+	return s
+}
+
+func setAttr(n *node.HTMLNode, key, value string) {
+	if n.Attributes == nil {
+		n.Attributes = make(map[string]string)
+	}
+	n.Attributes[key] = value
+	switch key {
+	case "id":
+		n.ID = value
+	case "class":
+		n.ClassList = strings.Fields(value)
+	}
+}
+
+// HasClass reports whether the first matched node carries class.
+func (s *Selection) HasClass(class string) bool {
+	if len(s.nodes) == 0 {
+		return false
+	}
+	return containsString(s.nodes[0].ClassList, class)
+}
+
+// AddClass adds class to every matched node.
+func (s *Selection) AddClass(class string) *Selection {
+	for _, n := range s.nodes {
+		if !containsString(n.ClassList, class) {
+			n.ClassList = append(n.ClassList, class)
+			setAttr(n, "class", strings.Join(n.ClassList, " "))
+		}
+	}
+	return s
+}
+
+// RemoveClass removes class from every matched node.
+func (s *Selection) RemoveClass(class string) *Selection {
+	for _, n := range s.nodes {
+		var kept []string
+		for _, c := range n.ClassList {
+			if c != class {
+				kept = append(kept, c)
+			}
+		}
+		n.ClassList = kept
+		setAttr(n, "class", strings.Join(n.ClassList, " "))
+	}
+	return s
+}
+
+// ToggleClass adds class where absent and removes it where present.
+func (s *Selection) ToggleClass(class string) *Selection {
+	for _, n := range s.nodes {
+		if containsString(n.ClassList, class) {
+			NewSelection(n).RemoveClass(class)
+		} else {
+			NewSelection(n).AddClass(class)
+		}
+	}
+	return s
+}
+
+// Append appends a clone of content as the last child of every matched node.
+func (s *Selection) Append(content *node.HTMLNode) *Selection {
+	for _, n := range s.nodes {
+		child := Clone(content)
+		child.Parent = n
+		n.Children = append(n.Children, child)
+		node.RecomputePaths(child)
+	}
+	return s
+}
+
+// Prepend inserts a clone of content as the first child of every matched node.
+func (s *Selection) Prepend(content *node.HTMLNode) *Selection {
+	for _, n := range s.nodes {
+		child := Clone(content)
+		child.Parent = n
+		n.Children = append([]*node.HTMLNode{child}, n.Children...)
+		node.RecomputePaths(child)
+	}
+	return s
+}
+
+// Before inserts a clone of content immediately before every matched node.
+func (s *Selection) Before(content *node.HTMLNode) *Selection {
+	for _, n := range s.nodes {
+		insertSibling(n, content, 0)
+	}
+	return s
+}
+
+// After inserts a clone of content immediately after every matched node.
+func (s *Selection) After(content *node.HTMLNode) *Selection {
+	for _, n := range s.nodes {
+		insertSibling(n, content, 1)
+	}
+	return s
+}
+
+func insertSibling(n, content *node.HTMLNode, offset int) {
+	if n.Parent == nil {
+		return
+	}
+	p := n.Parent
+	sibling := Clone(content)
+	sibling.Parent = p
+	for i, c := range p.Children {
+		if c == n {
+			idx := i + offset
+			p.Children = append(p.Children[:idx:idx], append([]*node.HTMLNode{sibling}, p.Children[idx:]...)...)
+			break
+		}
+	}
+	node.RecomputePaths(sibling)
+}
+
+// Remove detaches every matched node from its parent.
+func (s *Selection) Remove() *Selection {
+	for _, n := range s.nodes {
+		if n.Parent == nil {
+			continue
+		}
+		p := n.Parent
+		var kept []*node.HTMLNode
+		for _, c := range p.Children {
+			if c != n {
+				kept = append(kept, c)
+			}
+		}
+		p.Children = kept
+		n.Parent = nil
+	}
+	return s
+}
+
+// ReplaceWith replaces every matched node with a clone of content.
+func (s *Selection) ReplaceWith(content *node.HTMLNode) *Selection {
+	for _, n := range s.nodes {
+		if n.Parent == nil {
+			continue
+		}
+		p := n.Parent
+		replacement := Clone(content)
+		replacement.Parent = p
+		for i, c := range p.Children {
+			if c == n {
+				p.Children[i] = replacement
+				break
+			}
+		}
+		n.Parent = nil
+		node.RecomputePaths(replacement)
+	}
+	return s
+}
+
+// Clone deep-copies n and all of its descendants, relinking Parent pointers
+// within the copy. The returned node's Parent is nil until attached.
+func Clone(n *node.HTMLNode) *node.HTMLNode {
+	if n == nil {
+		return nil
+	}
+	clone := &node.HTMLNode{
+		Type:         n.Type,
+		TagName:      n.TagName,
+		TextContent:  n.TextContent,
+		ID:           n.ID,
+		ComputedPath: n.ComputedPath,
+		SelectorPath: n.SelectorPath,
+	}
+	if n.Attributes != nil {
+		clone.Attributes = make(map[string]string, len(n.Attributes))
+		for k, v := range n.Attributes {
+			clone.Attributes[k] = v
+		}
+	}
+	if n.ClassList != nil {
+		clone.ClassList = append([]string(nil), n.ClassList...)
+	}
+	for _, c := range n.Children {
+		childClone := Clone(c)
+		childClone.Parent = clone
+		clone.Children = append(clone.Children, childClone)
+	}
+	return clone
+}
+
+func elementSiblingAfter(n *node.HTMLNode) *node.HTMLNode {
+	if n.Parent == nil {
+		return nil
+	}
+	siblings := n.Parent.Children
+	for i, c := range siblings {
+		if c == n {
+			for j := i + 1; j < len(siblings); j++ {
+				if siblings[j].Type == "element" {
+					return siblings[j]
+				}
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+func elementSiblingBefore(n *node.HTMLNode) *node.HTMLNode {
+	if n.Parent == nil {
+		return nil
+	}
+	siblings := n.Parent.Children
+	for i, c := range siblings {
+		if c == n {
+			for j := i - 1; j >= 0; j-- {
+				if siblings[j].Type == "element" {
+					return siblings[j]
+				}
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// toHTMLNode converts a *node.HTMLNode subtree back into an *html.Node tree
+// so it can be serialized with golang.org/x/net/html.Render.
+func toHTMLNode(n *node.HTMLNode) *html.Node {
+	switch n.Type {
+	case "text":
+		return &html.Node{Type: html.TextNode, Data: n.TextContent}
+	case "element":
+		hn := &html.Node{
+			Type:     html.ElementNode,
+			Data:     n.TagName,
+			DataAtom: atom.Lookup([]byte(n.TagName)),
+		}
+		for k, v := range n.Attributes {
+			hn.Attr = append(hn.Attr, html.Attribute{Key: k, Val: v})
+		}
+		for _, c := range n.Children {
+			hn.AppendChild(toHTMLNode(c))
+		}
+		return hn
+	default: // "document" and anything else renders as a fragment of its children
+		hn := &html.Node{Type: html.DocumentNode}
+		for _, c := range n.Children {
+			hn.AppendChild(toHTMLNode(c))
+		}
+		return hn
+	}
+}
+
+func dedupe(nodes []*node.HTMLNode) []*node.HTMLNode {
+	seen := make(map[*node.HTMLNode]bool, len(nodes))
+	out := make([]*node.HTMLNode, 0, len(nodes))
+	for _, n := range nodes {
+		if !seen[n] {
+			seen[n] = true
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+func containsString(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
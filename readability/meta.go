@@ -0,0 +1,77 @@
+package readability
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/EslamAhmed171/HTML-Parser/node"
+	"github.com/EslamAhmed171/HTML-Parser/selector"
+)
+
+type pageMeta struct {
+	title    string
+	byline   string
+	date     string
+	lang     string
+	topImage string
+}
+
+// siteSuffixSep matches the common " | Site Name" / " - Site Name" tail on
+// <title> text so it can be trimmed when no og:title is present.
+var siteSuffixSep = regexp.MustCompile(`\s+[|\-–—]\s+\S.*$`)
+
+func extractMeta(root *node.HTMLNode) pageMeta {
+	var m pageMeta
+
+	if html := findFirst(root, "html"); html != nil {
+		m.lang = html.Attributes["lang"]
+	}
+
+	if og := metaContent(root, "og:title"); og != "" {
+		m.title = og
+	} else if titleNode := findFirst(root, "title"); titleNode != nil {
+		m.title = siteSuffixSep.ReplaceAllString(textOf(titleNode), "")
+	}
+
+	if a := findFirst(root, `[rel="author"]`); a != nil {
+		m.byline = strings.TrimSpace(textOf(a))
+	} else if a := findFirst(root, `[itemprop="author"]`); a != nil {
+		m.byline = strings.TrimSpace(textOf(a))
+	}
+
+	if t := findFirst(root, "time[datetime]"); t != nil {
+		m.date = t.Attributes["datetime"]
+	} else if d := metaContent(root, "article:published_time"); d != "" {
+		m.date = d
+	}
+
+	if img := metaContent(root, "og:image"); img != "" {
+		m.topImage = img
+	}
+
+	return m
+}
+
+func findFirst(root *node.HTMLNode, sel string) *node.HTMLNode {
+	compiled, err := selector.Compile(sel)
+	if err != nil {
+		return nil
+	}
+	matches := compiled.MatchAll(root)
+	if len(matches) == 0 {
+		return nil
+	}
+	return matches[0]
+}
+
+// metaContent returns the content attribute of the first
+// <meta property="key" content="..."> or <meta name="key" content="...">.
+func metaContent(root *node.HTMLNode, key string) string {
+	if m := findFirst(root, `meta[property="`+key+`"]`); m != nil {
+		return m.Attributes["content"]
+	}
+	if m := findFirst(root, `meta[name="`+key+`"]`); m != nil {
+		return m.Attributes["content"]
+	}
+	return ""
+}
@@ -0,0 +1,237 @@
+// Package readability extracts the main article body of a page, Mozilla
+// Readability-style: score candidate containers, propagate scores to
+// ancestors, pick the top-scoring one, and strip its low-scoring children.
+package readability
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/EslamAhmed171/HTML-Parser/node"
+	"github.com/EslamAhmed171/HTML-Parser/pkg/dom"
+)
+
+// Article is the result of a readability pass: the cleaned content subtree
+// plus the metadata extracted alongside it.
+type Article struct {
+	Title         string
+	Byline        string
+	PublishedDate string
+	Lang          string
+	Excerpt       string
+	TopImage      string
+	Content       *node.HTMLNode
+}
+
+// adOrShareWidget matches class/id tokens commonly used by ad slots, share
+// buttons and other boilerplate that readability should discard even when
+// it sits inside an otherwise high-scoring container.
+var adOrShareWidget = regexp.MustCompile(`(?i)(^|[-_])(ad|ads|advert|banner|share|social|sidebar|promo|widget|popup|newsletter|cookie|related|comment)([-_]|$)`)
+
+// Extract identifies root's main article body and returns a cleaned
+// subtree plus metadata. It does not mutate root.
+func Extract(root *node.HTMLNode) (*Article, error) {
+	if root == nil {
+		return nil, fmt.Errorf("readability: root is nil")
+	}
+
+	meta := extractMeta(root)
+
+	scores := map[*node.HTMLNode]float64{}
+	scoreContainers(root, scores)
+	if len(scores) == 0 {
+		return nil, fmt.Errorf("readability: no scorable content found")
+	}
+
+	total := propagateScores(scores)
+
+	var best *node.HTMLNode
+	bestScore := math.Inf(-1)
+	for n, s := range total {
+		if s > bestScore {
+			bestScore, best = s, n
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("readability: no content candidate found")
+	}
+
+	content := pruneClone(best, bestScore, total)
+
+	return &Article{
+		Title:         meta.title,
+		Byline:        meta.byline,
+		PublishedDate: meta.date,
+		Lang:          meta.lang,
+		Excerpt:       excerptOf(content, 240),
+		TopImage:      meta.topImage,
+		Content:       content,
+	}, nil
+}
+
+// RenderHTML serializes the extracted article content back to HTML.
+func (a *Article) RenderHTML(w io.Writer) error {
+	if a.Content == nil {
+		return nil
+	}
+	return dom.NewSelection(a.Content).Render(w)
+}
+
+func tagBaseScore(tag string) float64 {
+	switch tag {
+	case "article":
+		return 25
+	case "section", "main":
+		return 10
+	case "div":
+		return 5
+	case "p", "pre", "td", "blockquote", "li":
+		return 3
+	case "nav", "aside", "footer", "form", "header":
+		return -25
+	default:
+		return 0
+	}
+}
+
+func isScorable(tag string) bool {
+	switch tag {
+	case "script", "style", "noscript", "html", "head", "title", "meta", "link", "br", "hr", "img":
+		return false
+	default:
+		return true
+	}
+}
+
+// scoreContainers assigns each element an initial, purely local score based
+// on its tag, comma count and text length in its descendants, discounted by
+// link density and penalized when it looks like an ad/share widget.
+func scoreContainers(n *node.HTMLNode, scores map[*node.HTMLNode]float64) {
+	if n.Type == "element" && isScorable(n.TagName) {
+		text := textOf(n)
+		score := tagBaseScore(n.TagName)
+		score += float64(strings.Count(text, ","))
+		score += math.Min(float64(len(text))/100.0, 3.0)
+		score *= 1 - linkDensity(n)
+		if looksLikeBoilerplate(n) {
+			score -= 50
+		}
+		scores[n] = score
+	}
+	for _, c := range n.Children {
+		scoreContainers(c, scores)
+	}
+}
+
+func looksLikeBoilerplate(n *node.HTMLNode) bool {
+	if adOrShareWidget.MatchString(n.ID) {
+		return true
+	}
+	for _, c := range n.ClassList {
+		if adOrShareWidget.MatchString(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// propagateScores adds each scored node's score into every scored ancestor,
+// decaying geometrically with distance, and returns own-score + inherited
+// score per node.
+func propagateScores(scores map[*node.HTMLNode]float64) map[*node.HTMLNode]float64 {
+	total := make(map[*node.HTMLNode]float64, len(scores))
+	for n, s := range scores {
+		total[n] += s
+		decay := 0.7
+		for p := n.Parent; p != nil; p = p.Parent {
+			if _, ok := scores[p]; ok {
+				total[p] += s * decay
+			}
+			decay *= 0.7
+		}
+	}
+	return total
+}
+
+// pruneClone deep-copies best, dropping every descendant element whose
+// propagated score falls below a fraction of the winning candidate's score or that
+// looks like an ad/share widget, at any depth — not just among best's
+// direct children.
+func pruneClone(best *node.HTMLNode, bestScore float64, total map[*node.HTMLNode]float64) *node.HTMLNode {
+	threshold := bestScore * 0.2
+	clone := dom.Clone(best)
+	pruneBoilerplate(clone, best, threshold, total)
+	node.RecomputePaths(clone)
+	return clone
+}
+
+// pruneBoilerplate walks clone and orig in lockstep (they still share the
+// same child ordering) and drops any element that looks like an ad/share
+// widget or whose propagated score falls under threshold, then recurses
+// into whatever survives so boilerplate nested several levels deep (e.g. a
+// share-widget div inside a kept section) is removed too.
+func pruneBoilerplate(clone, orig *node.HTMLNode, threshold float64, total map[*node.HTMLNode]float64) {
+	var kept []*node.HTMLNode
+	for i, c := range clone.Children {
+		origChild := orig.Children[i]
+		if c.Type == "element" {
+			if looksLikeBoilerplate(origChild) {
+				continue
+			}
+			if s, scored := total[origChild]; scored && s < threshold {
+				continue
+			}
+			pruneBoilerplate(c, origChild, threshold, total)
+		}
+		kept = append(kept, c)
+	}
+	clone.Children = kept
+}
+
+func textOf(n *node.HTMLNode) string {
+	var b strings.Builder
+	var walk func(n *node.HTMLNode)
+	walk = func(n *node.HTMLNode) {
+		if n.Type == "text" {
+			b.WriteString(n.TextContent)
+			return
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(n)
+	return b.String()
+}
+
+func linkDensity(n *node.HTMLNode) float64 {
+	total := len(textOf(n))
+	if total == 0 {
+		return 0
+	}
+	linkChars := 0
+	var walk func(n *node.HTMLNode)
+	walk = func(n *node.HTMLNode) {
+		if n.Type == "element" && n.TagName == "a" {
+			linkChars += len(textOf(n))
+			return
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(n)
+	return float64(linkChars) / float64(total)
+}
+
+func excerptOf(n *node.HTMLNode, maxLen int) string {
+	text := strings.Join(strings.Fields(textOf(n)), " ")
+	runes := []rune(text)
+	if len(runes) <= maxLen {
+		return text
+	}
+	return strings.TrimSpace(string(runes[:maxLen])) + "…"
+}
@@ -0,0 +1,73 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+
+	"github.com/EslamAhmed171/HTML-Parser/node"
+)
+
+func parseForTest(t *testing.T, src string) *node.HTMLNode {
+	t.Helper()
+	doc, err := html.Parse(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("html.Parse: %v", err)
+	}
+	return node.RenderNode(doc, nil, node.ParserConfig{NormalizeWhitespace: true}, "", "")
+}
+
+func TestExtractPrunesNestedBoilerplate(t *testing.T) {
+	const src = `<html><body>
+		<article>
+			<p>Paragraph one of the real article text, long enough to score well on its own merits.</p>
+			<div class="container">
+				<div class="ad-banner">Buy now! Limited offer just for you today, act fast.</div>
+			</div>
+			<p>Paragraph two, also long enough to carry a healthy chunk of the article's score.</p>
+			<div class="container">
+				<div class="share-widget">Share this article on social media with your friends now.</div>
+			</div>
+		</article>
+	</body></html>`
+
+	root := parseForTest(t, src)
+	article, err := Extract(root)
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+
+	var rendered strings.Builder
+	if err := article.RenderHTML(&rendered); err != nil {
+		t.Fatalf("RenderHTML: %v", err)
+	}
+	out := rendered.String()
+
+	if strings.Contains(out, "ad-banner") || strings.Contains(out, "Buy now") {
+		t.Errorf("expected nested ad banner to be pruned, got: %s", out)
+	}
+	if strings.Contains(out, "share-widget") || strings.Contains(out, "Share this article on social") {
+		t.Errorf("expected nested share widget to be pruned, got: %s", out)
+	}
+	if !strings.Contains(out, "Paragraph one") || !strings.Contains(out, "Paragraph two") {
+		t.Errorf("expected both real paragraphs to survive, got: %s", out)
+	}
+}
+
+// TestExcerptOfTruncatesOnRuneBoundary guards against byte-slicing a string
+// whose maxLen-th byte falls in the middle of a multi-byte UTF-8 rune, which
+// would otherwise splice in the U+FFFD replacement character.
+func TestExcerptOfTruncatesOnRuneBoundary(t *testing.T) {
+	root := parseForTest(t, "<p>"+strings.Repeat("é", 130)+"</p>")
+
+	got := excerptOf(root, 120)
+
+	if strings.ContainsRune(got, '�') {
+		t.Errorf("excerptOf produced a replacement character: %q", got)
+	}
+	want := strings.Repeat("é", 120) + "…"
+	if got != want {
+		t.Errorf("excerptOf = %q, want %q", got, want)
+	}
+}
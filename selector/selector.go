@@ -0,0 +1,747 @@
+// Package selector implements a CSS3-ish selector engine that parses and
+// matches selector lists against *node.HTMLNode trees. It replaces the
+// single-compound, descendant-only matching that used to live in
+// GetHTMLNodeBySelector/matchesSelector.
+package selector
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/EslamAhmed171/HTML-Parser/node"
+)
+
+type combinator byte
+
+const (
+	combNone       combinator = 0
+	combDescendant combinator = ' '
+	combChild      combinator = '>'
+	combAdjacent   combinator = '+'
+	combSibling    combinator = '~'
+)
+
+type attrOp int
+
+const (
+	attrExists attrOp = iota
+	attrEquals
+	attrIncludes  // ~=
+	attrDashMatch // |=
+	attrPrefix    // ^=
+	attrSuffix    // $=
+	attrSubstring // *=
+)
+
+type attrSelector struct {
+	name       string
+	op         attrOp
+	value      string
+	ignoreCase bool
+}
+
+type pseudoKind int
+
+const (
+	pseudoFirstChild pseudoKind = iota
+	pseudoLastChild
+	pseudoNthChild
+	pseudoNthOfType
+	pseudoNot
+	pseudoHas
+	pseudoEmpty
+	pseudoRoot
+)
+
+type pseudoSelector struct {
+	kind  pseudoKind
+	a, b  int
+	inner Selector
+}
+
+// compoundSelector is one "tag#id.class[attr]:pseudo" unit.
+type compoundSelector struct {
+	combinator combinator // combinator joining this compound to the previous one; combNone for the first
+	tag        string     // "" means unconstrained
+	universal  bool
+	id         string
+	classes    []string
+	attrs      []attrSelector
+	pseudos    []pseudoSelector
+}
+
+type complexSelector struct {
+	compounds []*compoundSelector // source order; compounds[0].combinator == combNone
+}
+
+// Selector is a compiled, comma-separated selector list.
+type Selector struct {
+	list []*complexSelector
+}
+
+// Compile parses a CSS selector list (comma-separated complex selectors)
+// into a Selector that can be matched against *node.HTMLNode trees.
+func Compile(sel string) (Selector, error) {
+	parts, err := splitTopLevel(sel, ',')
+	if err != nil {
+		return Selector{}, err
+	}
+
+	var list []*complexSelector
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		cs, err := parseComplexSelector(p)
+		if err != nil {
+			return Selector{}, err
+		}
+		list = append(list, cs)
+	}
+	if len(list) == 0 {
+		return Selector{}, fmt.Errorf("selector: empty selector %q", sel)
+	}
+	return Selector{list: list}, nil
+}
+
+// Match reports whether n matches any complex selector in the list.
+func (s Selector) Match(n *node.HTMLNode) bool {
+	for _, cs := range s.list {
+		if matchComplex(cs, n) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchAll walks root's subtree (root included) and returns every node that
+// matches, in document order.
+func (s Selector) MatchAll(root *node.HTMLNode) []*node.HTMLNode {
+	var out []*node.HTMLNode
+	var walk func(n *node.HTMLNode)
+	walk = func(n *node.HTMLNode) {
+		if n == nil {
+			return
+		}
+		if s.Match(n) {
+			out = append(out, n)
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(root)
+	return out
+}
+
+// RequiresFollowingSiblings reports whether matching the selector against a
+// node can depend on siblings that come after it in the document (currently
+// only :last-child), including occurrences nested inside :not()/:has().
+// Callers that decide a match before a node's later siblings exist — e.g. a
+// streaming tokenizer — cannot evaluate such a selector correctly.
+func (s Selector) RequiresFollowingSiblings() bool {
+	for _, cs := range s.list {
+		for _, comp := range cs.compounds {
+			for _, p := range comp.pseudos {
+				if pseudoRequiresFollowingSiblings(p) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func pseudoRequiresFollowingSiblings(p pseudoSelector) bool {
+	switch p.kind {
+	case pseudoLastChild:
+		return true
+	case pseudoNot, pseudoHas:
+		return p.inner.RequiresFollowingSiblings()
+	}
+	return false
+}
+
+// Filter returns the subset of nodes that match the selector, preserving order.
+func (s Selector) Filter(nodes []*node.HTMLNode) []*node.HTMLNode {
+	var out []*node.HTMLNode
+	for _, n := range nodes {
+		if s.Match(n) {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// splitTopLevel splits s on sep, ignoring occurrences nested inside
+// [...] or (...).
+func splitTopLevel(s string, sep rune) ([]string, error) {
+	var parts []string
+	var cur strings.Builder
+	depth := 0
+	for _, r := range s {
+		switch r {
+		case '[', '(':
+			depth++
+			cur.WriteRune(r)
+		case ']', ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("selector: unbalanced brackets in %q", s)
+			}
+			cur.WriteRune(r)
+		case sep:
+			if depth == 0 {
+				parts = append(parts, cur.String())
+				cur.Reset()
+				continue
+			}
+			cur.WriteRune(r)
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("selector: unbalanced brackets in %q", s)
+	}
+	parts = append(parts, cur.String())
+	return parts, nil
+}
+
+// parseComplexSelector parses one combinator-joined chain, e.g. "div.foo > span + a".
+func parseComplexSelector(s string) (*complexSelector, error) {
+	tokens, combs, err := splitCombinators(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("selector: empty selector %q", s)
+	}
+
+	cs := &complexSelector{}
+	for i, tok := range tokens {
+		compound, err := parseCompound(tok)
+		if err != nil {
+			return nil, err
+		}
+		compound.combinator = combs[i]
+		cs.compounds = append(cs.compounds, compound)
+	}
+	return cs, nil
+}
+
+// splitCombinators tokenizes a complex selector into compound-selector
+// strings and the combinator that precedes each one (combNone for the first).
+func splitCombinators(s string) ([]string, []combinator, error) {
+	var compounds []string
+	var combs []combinator
+	var cur strings.Builder
+	depth := 0
+	pending := combNone
+
+	flush := func() {
+		if cur.Len() == 0 {
+			return
+		}
+		compounds = append(compounds, strings.TrimSpace(cur.String()))
+		combs = append(combs, pending)
+		cur.Reset()
+	}
+
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		switch c {
+		case '[', '(':
+			depth++
+			cur.WriteRune(c)
+		case ']', ')':
+			depth--
+			cur.WriteRune(c)
+		case '>', '+', '~':
+			if depth > 0 {
+				cur.WriteRune(c)
+				continue
+			}
+			flush()
+			switch c {
+			case '>':
+				pending = combChild
+			case '+':
+				pending = combAdjacent
+			case '~':
+				pending = combSibling
+			}
+		case ' ', '\t', '\n':
+			if depth > 0 {
+				cur.WriteRune(c)
+				continue
+			}
+			if cur.Len() > 0 {
+				flush()
+				pending = combDescendant
+			}
+		default:
+			cur.WriteRune(c)
+		}
+	}
+	flush()
+	if depth != 0 {
+		return nil, nil, fmt.Errorf("selector: unbalanced brackets in %q", s)
+	}
+	if len(combs) > 0 {
+		combs[0] = combNone
+	}
+	return compounds, combs, nil
+}
+
+func isIdentChar(b byte) bool {
+	return b == '-' || b == '_' ||
+		(b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9')
+}
+
+// parseCompound parses a single "tag#id.class[attr]:pseudo" unit.
+func parseCompound(s string) (*compoundSelector, error) {
+	cs := &compoundSelector{}
+	i := 0
+	n := len(s)
+
+	if i < n && s[i] != '#' && s[i] != '.' && s[i] != '[' && s[i] != ':' {
+		j := i
+		for j < n && s[j] != '#' && s[j] != '.' && s[j] != '[' && s[j] != ':' {
+			j++
+		}
+		tag := s[i:j]
+		if tag == "*" {
+			cs.universal = true
+		} else {
+			cs.tag = strings.ToLower(tag)
+		}
+		i = j
+	}
+
+	for i < n {
+		switch s[i] {
+		case '#':
+			j := i + 1
+			for j < n && s[j] != '#' && s[j] != '.' && s[j] != '[' && s[j] != ':' {
+				j++
+			}
+			cs.id = s[i+1 : j]
+			i = j
+		case '.':
+			j := i + 1
+			for j < n && s[j] != '#' && s[j] != '.' && s[j] != '[' && s[j] != ':' {
+				j++
+			}
+			cs.classes = append(cs.classes, s[i+1:j])
+			i = j
+		case '[':
+			end := strings.IndexByte(s[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("selector: unterminated attribute selector in %q", s)
+			}
+			end += i
+			attr, err := parseAttrSelector(s[i+1 : end])
+			if err != nil {
+				return nil, err
+			}
+			cs.attrs = append(cs.attrs, attr)
+			i = end + 1
+		case ':':
+			j := i + 1
+			for j < n && isIdentChar(s[j]) {
+				j++
+			}
+			name := s[i+1 : j]
+			var arg string
+			if j < n && s[j] == '(' {
+				depth := 1
+				k := j + 1
+				for k < n && depth > 0 {
+					switch s[k] {
+					case '(':
+						depth++
+					case ')':
+						depth--
+					}
+					k++
+				}
+				if depth != 0 {
+					return nil, fmt.Errorf("selector: unterminated pseudo-class arguments in %q", s)
+				}
+				arg = s[j+1 : k-1]
+				j = k
+			}
+			ps, err := parsePseudo(name, arg)
+			if err != nil {
+				return nil, err
+			}
+			cs.pseudos = append(cs.pseudos, ps)
+			i = j
+		default:
+			return nil, fmt.Errorf("selector: unexpected character %q in %q", s[i], s)
+		}
+	}
+
+	return cs, nil
+}
+
+var attrSelectorRe = regexp.MustCompile(`^\s*([-\w]+)\s*(?:([~|^$*]?=)\s*(?:"([^"]*)"|'([^']*)'|([^\s\]]+)))?\s*(i|I)?\s*$`)
+
+func parseAttrSelector(s string) (attrSelector, error) {
+	m := attrSelectorRe.FindStringSubmatch(s)
+	if m == nil {
+		return attrSelector{}, fmt.Errorf("selector: invalid attribute selector [%s]", s)
+	}
+	as := attrSelector{name: m[1], ignoreCase: m[6] != ""}
+	if m[2] == "" {
+		as.op = attrExists
+		return as, nil
+	}
+	switch m[2] {
+	case "=":
+		as.op = attrEquals
+	case "~=":
+		as.op = attrIncludes
+	case "|=":
+		as.op = attrDashMatch
+	case "^=":
+		as.op = attrPrefix
+	case "$=":
+		as.op = attrSuffix
+	case "*=":
+		as.op = attrSubstring
+	default:
+		return attrSelector{}, fmt.Errorf("selector: unknown attribute operator %q", m[2])
+	}
+	for _, v := range m[3:6] {
+		if v != "" {
+			as.value = v
+			break
+		}
+	}
+	return as, nil
+}
+
+func parsePseudo(name, arg string) (pseudoSelector, error) {
+	switch strings.ToLower(name) {
+	case "first-child":
+		return pseudoSelector{kind: pseudoFirstChild}, nil
+	case "last-child":
+		return pseudoSelector{kind: pseudoLastChild}, nil
+	case "empty":
+		return pseudoSelector{kind: pseudoEmpty}, nil
+	case "root":
+		return pseudoSelector{kind: pseudoRoot}, nil
+	case "nth-child", "nth-of-type":
+		a, b, err := parseAnB(arg)
+		if err != nil {
+			return pseudoSelector{}, err
+		}
+		kind := pseudoNthChild
+		if strings.ToLower(name) == "nth-of-type" {
+			kind = pseudoNthOfType
+		}
+		return pseudoSelector{kind: kind, a: a, b: b}, nil
+	case "not":
+		inner, err := compileInner(arg)
+		if err != nil {
+			return pseudoSelector{}, err
+		}
+		return pseudoSelector{kind: pseudoNot, inner: inner}, nil
+	case "has":
+		inner, err := compileInner(arg)
+		if err != nil {
+			return pseudoSelector{}, err
+		}
+		return pseudoSelector{kind: pseudoHas, inner: inner}, nil
+	default:
+		return pseudoSelector{}, fmt.Errorf("selector: unsupported pseudo-class %q", name)
+	}
+}
+
+func compileInner(arg string) (Selector, error) {
+	return Compile(arg)
+}
+
+// parseAnB parses the An+B microsyntax used by :nth-child()/:nth-of-type().
+func parseAnB(s string) (a, b int, err error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch s {
+	case "odd":
+		return 2, 1, nil
+	case "even":
+		return 2, 0, nil
+	}
+	re := regexp.MustCompile(`^([+-]?\d*)n(?:\s*([+-]\s*\d+))?$|^([+-]?\d+)$`)
+	m := re.FindStringSubmatch(s)
+	if m == nil {
+		return 0, 0, fmt.Errorf("selector: invalid An+B expression %q", s)
+	}
+	if m[3] != "" {
+		n, _ := strconv.Atoi(m[3])
+		return 0, n, nil
+	}
+	aPart := m[1]
+	switch aPart {
+	case "", "+":
+		a = 1
+	case "-":
+		a = -1
+	default:
+		a, _ = strconv.Atoi(aPart)
+	}
+	if m[2] != "" {
+		b, _ = strconv.Atoi(strings.ReplaceAll(m[2], " ", ""))
+	}
+	return a, b, nil
+}
+
+func matchComplex(cs *complexSelector, n *node.HTMLNode) bool {
+	idx := len(cs.compounds) - 1
+	if idx < 0 || !matchCompound(cs.compounds[idx], n) {
+		return false
+	}
+	return matchAncestors(cs.compounds, idx, n)
+}
+
+func matchAncestors(compounds []*compoundSelector, idx int, n *node.HTMLNode) bool {
+	if idx == 0 {
+		return true
+	}
+	prevIdx := idx - 1
+	switch compounds[idx].combinator {
+	case combChild:
+		p := n.Parent
+		if p == nil {
+			return false
+		}
+		return matchCompound(compounds[prevIdx], p) && matchAncestors(compounds, prevIdx, p)
+	case combDescendant:
+		for p := n.Parent; p != nil; p = p.Parent {
+			if matchCompound(compounds[prevIdx], p) && matchAncestors(compounds, prevIdx, p) {
+				return true
+			}
+		}
+		return false
+	case combAdjacent:
+		prev := previousElementSibling(n)
+		if prev == nil {
+			return false
+		}
+		return matchCompound(compounds[prevIdx], prev) && matchAncestors(compounds, prevIdx, prev)
+	case combSibling:
+		for _, s := range precedingElementSiblings(n) {
+			if matchCompound(compounds[prevIdx], s) && matchAncestors(compounds, prevIdx, s) {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+func matchCompound(cs *compoundSelector, n *node.HTMLNode) bool {
+	if n.Type != "element" {
+		return false
+	}
+	if cs.tag != "" && cs.tag != n.TagName {
+		return false
+	}
+	if cs.id != "" && cs.id != n.ID {
+		return false
+	}
+	for _, c := range cs.classes {
+		if !containsString(n.ClassList, c) {
+			return false
+		}
+	}
+	for _, a := range cs.attrs {
+		if !matchAttr(a, n) {
+			return false
+		}
+	}
+	for _, p := range cs.pseudos {
+		if !matchPseudo(p, n) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchAttr(a attrSelector, n *node.HTMLNode) bool {
+	v, ok := n.Attributes[a.name]
+	if !ok {
+		return false
+	}
+	if a.op == attrExists {
+		return true
+	}
+	lv, lval := v, a.value
+	if a.ignoreCase {
+		lv, lval = strings.ToLower(lv), strings.ToLower(lval)
+	}
+	switch a.op {
+	case attrEquals:
+		return lv == lval
+	case attrIncludes:
+		for _, tok := range strings.Fields(lv) {
+			if tok == lval {
+				return true
+			}
+		}
+		return false
+	case attrDashMatch:
+		return lv == lval || strings.HasPrefix(lv, lval+"-")
+	case attrPrefix:
+		return lval != "" && strings.HasPrefix(lv, lval)
+	case attrSuffix:
+		return lval != "" && strings.HasSuffix(lv, lval)
+	case attrSubstring:
+		return lval != "" && strings.Contains(lv, lval)
+	}
+	return false
+}
+
+func matchPseudo(p pseudoSelector, n *node.HTMLNode) bool {
+	switch p.kind {
+	case pseudoRoot:
+		return n.Parent == nil || n.Parent.Type == "document"
+	case pseudoEmpty:
+		return len(n.Children) == 0
+	case pseudoFirstChild:
+		return previousElementSibling(n) == nil
+	case pseudoLastChild:
+		return nextElementSibling(n) == nil
+	case pseudoNthChild:
+		return matchAnB(p.a, p.b, elementIndex(n))
+	case pseudoNthOfType:
+		return matchAnB(p.a, p.b, elementIndexOfType(n))
+	case pseudoNot:
+		return !p.inner.Match(n)
+	case pseudoHas:
+		return hasDescendantMatch(p.inner, n)
+	}
+	return false
+}
+
+func matchAnB(a, b, pos int) bool {
+	if pos <= 0 {
+		return false
+	}
+	if a == 0 {
+		return pos == b
+	}
+	diff := pos - b
+	if diff%a != 0 {
+		return false
+	}
+	return diff/a >= 0
+}
+
+func hasDescendantMatch(sel Selector, n *node.HTMLNode) bool {
+	for _, c := range n.Children {
+		if sel.Match(c) || hasDescendantMatch(sel, c) {
+			return true
+		}
+	}
+	return false
+}
+
+func elementChildren(p *node.HTMLNode) []*node.HTMLNode {
+	var out []*node.HTMLNode
+	for _, c := range p.Children {
+		if c.Type == "element" {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func elementIndex(n *node.HTMLNode) int {
+	if n.Parent == nil {
+		return 1
+	}
+	for i, c := range elementChildren(n.Parent) {
+		if c == n {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+func elementIndexOfType(n *node.HTMLNode) int {
+	if n.Parent == nil {
+		return 1
+	}
+	idx := 0
+	for _, c := range elementChildren(n.Parent) {
+		if c.TagName != n.TagName {
+			continue
+		}
+		idx++
+		if c == n {
+			return idx
+		}
+	}
+	return 0
+}
+
+func previousElementSibling(n *node.HTMLNode) *node.HTMLNode {
+	if n.Parent == nil {
+		return nil
+	}
+	siblings := elementChildren(n.Parent)
+	for i, c := range siblings {
+		if c == n {
+			if i == 0 {
+				return nil
+			}
+			return siblings[i-1]
+		}
+	}
+	return nil
+}
+
+func nextElementSibling(n *node.HTMLNode) *node.HTMLNode {
+	if n.Parent == nil {
+		return nil
+	}
+	siblings := elementChildren(n.Parent)
+	for i, c := range siblings {
+		if c == n {
+			if i == len(siblings)-1 {
+				return nil
+			}
+			return siblings[i+1]
+		}
+	}
+	return nil
+}
+
+func precedingElementSiblings(n *node.HTMLNode) []*node.HTMLNode {
+	if n.Parent == nil {
+		return nil
+	}
+	siblings := elementChildren(n.Parent)
+	for i, c := range siblings {
+		if c == n {
+			return siblings[:i]
+		}
+	}
+	return nil
+}
+
+func containsString(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,176 @@
+package selector
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+
+	"github.com/EslamAhmed171/HTML-Parser/node"
+)
+
+func parseForTest(t *testing.T, src string) *node.HTMLNode {
+	t.Helper()
+	doc, err := html.Parse(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("html.Parse: %v", err)
+	}
+	return node.RenderNode(doc, nil, node.ParserConfig{NormalizeWhitespace: true}, "", "")
+}
+
+func matchTexts(t *testing.T, root *node.HTMLNode, sel string) []string {
+	t.Helper()
+	compiled, err := Compile(sel)
+	if err != nil {
+		t.Fatalf("Compile(%q): %v", sel, err)
+	}
+	var out []string
+	for _, n := range compiled.MatchAll(root) {
+		out = append(out, n.TagName)
+	}
+	return out
+}
+
+func TestCompileCompoundTagIDClass(t *testing.T) {
+	root := parseForTest(t, `<html><body>
+		<div class="foo" id="bar" type="text">a</div>
+		<div class="foo">b</div>
+		<span id="bar">c</span>
+	</body></html>`)
+
+	cases := []struct {
+		sel  string
+		want []string
+	}{
+		{"div", []string{"div", "div"}},
+		{"#bar", []string{"div", "span"}},
+		{".foo", []string{"div", "div"}},
+		{"div.foo", []string{"div", "div"}},
+		{`div.foo#bar[type="text"]`, []string{"div"}},
+		{"div#bar.foo", []string{"div"}},
+	}
+	for _, c := range cases {
+		got := matchTexts(t, root, c.sel)
+		if len(got) != len(c.want) {
+			t.Errorf("Compile(%q).MatchAll = %v, want %v", c.sel, got, c.want)
+		}
+	}
+}
+
+func TestCompileCompoundClassThenIDParsesSeparately(t *testing.T) {
+	cs, err := parseCompound("div.foo#bar")
+	if err != nil {
+		t.Fatalf("parseCompound: %v", err)
+	}
+	if len(cs.classes) != 1 || cs.classes[0] != "foo" {
+		t.Errorf("classes = %v, want [foo]", cs.classes)
+	}
+	if cs.id != "bar" {
+		t.Errorf("id = %q, want \"bar\"", cs.id)
+	}
+}
+
+func TestAttributeOperators(t *testing.T) {
+	root := parseForTest(t, `<html><body>
+		<a href="https://example.com/path" lang="en-US" title="hello world">a</a>
+		<a href="/relative" lang="en-GB" title="goodbye">b</a>
+	</body></html>`)
+
+	cases := []struct {
+		sel  string
+		want int
+	}{
+		{`a[href]`, 2},
+		{`a[href="/relative"]`, 1},
+		{`a[href^="https"]`, 1},
+		{`a[href$="path"]`, 1},
+		{`a[href*="example"]`, 1},
+		{`a[lang|="en"]`, 2},
+		{`a[title~="hello"]`, 1},
+	}
+	for _, c := range cases {
+		got := matchTexts(t, root, c.sel)
+		if len(got) != c.want {
+			t.Errorf("Compile(%q).MatchAll matched %d, want %d", c.sel, len(got), c.want)
+		}
+	}
+}
+
+func TestPseudoClasses(t *testing.T) {
+	root := parseForTest(t, `<ul>
+		<li>one</li>
+		<li>two</li>
+		<li>three</li>
+	</ul>`)
+
+	cases := []struct {
+		sel  string
+		want int
+	}{
+		{"li:first-child", 1},
+		{"li:last-child", 1},
+		{"li:nth-child(2)", 1},
+		{"li:nth-child(odd)", 2},
+		{"li:empty", 0},
+		{"ul:has(li)", 1},
+		{"li:not(:first-child)", 2},
+	}
+	for _, c := range cases {
+		got := matchTexts(t, root, c.sel)
+		if len(got) != c.want {
+			t.Errorf("Compile(%q).MatchAll matched %d, want %d", c.sel, len(got), c.want)
+		}
+	}
+}
+
+func TestCombinators(t *testing.T) {
+	root := parseForTest(t, `<div>
+		<section>
+			<h1>Title</h1>
+			<p>first</p>
+			<p>second</p>
+		</section>
+		<p>outside</p>
+	</div>`)
+
+	cases := []struct {
+		sel  string
+		want int
+	}{
+		{"section p", 2},
+		{"div > p", 1},
+		{"h1 + p", 1},
+		{"h1 ~ p", 2},
+	}
+	for _, c := range cases {
+		got := matchTexts(t, root, c.sel)
+		if len(got) != c.want {
+			t.Errorf("Compile(%q).MatchAll matched %d, want %d", c.sel, len(got), c.want)
+		}
+	}
+}
+
+func TestRequiresFollowingSiblings(t *testing.T) {
+	cases := []struct {
+		sel  string
+		want bool
+	}{
+		{"li", false},
+		{"li:first-child", false},
+		{"li:nth-child(2)", false},
+		{"li:last-child", true},
+		{"ul > li:last-child", true},
+		{"li:not(:last-child)", true},
+		{"div:has(li:last-child)", true},
+		{"div:has(li)", false},
+	}
+	for _, c := range cases {
+		sel, err := Compile(c.sel)
+		if err != nil {
+			t.Fatalf("Compile(%q): %v", c.sel, err)
+		}
+		if got := sel.RequiresFollowingSiblings(); got != c.want {
+			t.Errorf("Compile(%q).RequiresFollowingSiblings() = %v, want %v", c.sel, got, c.want)
+		}
+	}
+}
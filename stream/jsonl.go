@@ -0,0 +1,104 @@
+package stream
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// jsonlRecord is one line of -format jsonl output: a single node plus just
+// enough path context to place it, without nesting the rest of the subtree.
+type jsonlRecord struct {
+	Type        string            `json:"type"`
+	TagName     string            `json:"tagName,omitempty"`
+	Attributes  map[string]string `json:"attributes,omitempty"`
+	TextContent string            `json:"textContent,omitempty"`
+	ID          string            `json:"id,omitempty"`
+	ClassList   []string          `json:"classList,omitempty"`
+	Path        string            `json:"path,omitempty"`
+	Depth       int               `json:"depth"`
+}
+
+type elementFrame struct {
+	tag   string
+	attrs map[string]string
+	text  strings.Builder
+}
+
+// JSONLHandler writes one JSON object per line as each element's closing
+// tag is reached, keeping only an O(depth) stack of open ancestors in
+// memory rather than the whole document.
+type JSONLHandler struct {
+	enc   *json.Encoder
+	stack []*elementFrame
+}
+
+// NewJSONLHandler returns a Handler that streams node records to w.
+func NewJSONLHandler(w io.Writer) *JSONLHandler {
+	return &JSONLHandler{enc: json.NewEncoder(w)}
+}
+
+func (h *JSONLHandler) StartElement(tag string, attrs map[string]string) {
+	h.stack = append(h.stack, &elementFrame{tag: tag, attrs: attrs})
+}
+
+func (h *JSONLHandler) EndElement(tag string) {
+	if len(h.stack) == 0 {
+		return
+	}
+	frame := h.stack[len(h.stack)-1]
+	h.stack = h.stack[:len(h.stack)-1]
+
+	rec := jsonlRecord{
+		Type:        "element",
+		TagName:     frame.tag,
+		Attributes:  frame.attrs,
+		TextContent: strings.TrimSpace(frame.text.String()),
+		Depth:       len(h.stack),
+		Path:        h.pathWith(frame.tag),
+	}
+	if id, ok := frame.attrs["id"]; ok {
+		rec.ID = id
+	}
+	if class, ok := frame.attrs["class"]; ok {
+		rec.ClassList = strings.Fields(class)
+	}
+	h.enc.Encode(rec)
+}
+
+func (h *JSONLHandler) Text(data string) {
+	if len(h.stack) == 0 {
+		h.enc.Encode(jsonlRecord{Type: "text", TextContent: data})
+		return
+	}
+	top := h.stack[len(h.stack)-1]
+	if top.text.Len() > 0 {
+		top.text.WriteByte(' ')
+	}
+	top.text.WriteString(data)
+}
+
+func (h *JSONLHandler) Comment(data string) {
+	h.enc.Encode(jsonlRecord{Type: "comment", TextContent: data, Depth: len(h.stack), Path: h.path()})
+}
+
+func (h *JSONLHandler) Doctype(data string) {
+	h.enc.Encode(jsonlRecord{Type: "doctype", TextContent: data})
+}
+
+func (h *JSONLHandler) path() string {
+	tags := make([]string, len(h.stack))
+	for i, f := range h.stack {
+		tags[i] = f.tag
+	}
+	return strings.Join(tags, " > ")
+}
+
+func (h *JSONLHandler) pathWith(tag string) string {
+	tags := make([]string, 0, len(h.stack)+1)
+	for _, f := range h.stack {
+		tags = append(tags, f.tag)
+	}
+	tags = append(tags, tag)
+	return strings.Join(tags, " > ")
+}
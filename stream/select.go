@@ -0,0 +1,86 @@
+package stream
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/EslamAhmed171/HTML-Parser/node"
+	"github.com/EslamAhmed171/HTML-Parser/selector"
+)
+
+// SelectHandler tokenizes a document while keeping, at any moment, the open
+// ancestor chain with the direct children of each open ancestor (text nodes
+// and emptied shells of closed elements) plus the full content of any open
+// element that matches sel. As each element closes it is tested against sel
+// and handed to emit if it matches; its children and text are then dropped
+// unless an open ancestor matches sel, so memory is not spent on closed
+// subtrees outside a match.
+type SelectHandler struct {
+	sel   selector.Selector
+	root  *node.HTMLNode
+	stack []*node.HTMLNode
+	emit  func(*node.HTMLNode)
+}
+
+// NewSelectHandler returns a Handler that emits every subtree matching sel
+// as soon as its closing tag is seen. It rejects selectors whose result can
+// depend on siblings that haven't been parsed yet at that point, such as
+// :last-child; run those against the full DOM instead.
+func NewSelectHandler(sel selector.Selector, emit func(*node.HTMLNode)) (*SelectHandler, error) {
+	if sel.RequiresFollowingSiblings() {
+		return nil, fmt.Errorf("stream: selector depends on following siblings (e.g. :last-child), which streaming mode can't evaluate until they're parsed; use the full-DOM selector engine instead")
+	}
+	root := &node.HTMLNode{Type: "document"}
+	return &SelectHandler{sel: sel, root: root, stack: []*node.HTMLNode{root}, emit: emit}, nil
+}
+
+func (h *SelectHandler) top() *node.HTMLNode { return h.stack[len(h.stack)-1] }
+
+func (h *SelectHandler) StartElement(tag string, attrs map[string]string) {
+	n := &node.HTMLNode{Type: "element", TagName: tag, Attributes: attrs, Parent: h.top()}
+	if id, ok := attrs["id"]; ok {
+		n.ID = id
+	}
+	if class, ok := attrs["class"]; ok {
+		n.ClassList = strings.Fields(class)
+	}
+	node.RecomputePaths(n)
+
+	parent := h.top()
+	parent.Children = append(parent.Children, n)
+	h.stack = append(h.stack, n)
+}
+
+func (h *SelectHandler) EndElement(tag string) {
+	if len(h.stack) <= 1 {
+		return
+	}
+	n := h.stack[len(h.stack)-1]
+	h.stack = h.stack[:len(h.stack)-1]
+
+	if h.sel.Match(n) {
+		h.emit(n)
+	}
+
+	// n's own closing tag has been seen, so its subtree can never grow any
+	// further. A matching open ancestor is emitted with its whole subtree
+	// when it closes, so n's content is kept while one exists; otherwise it
+	// is dropped. The shell (tag/id/class/Parent) stays in its parent's
+	// Children slice so later siblings can still resolve :nth-child, +, and ~
+	// against it.
+	for _, anc := range h.stack {
+		if h.sel.Match(anc) {
+			return
+		}
+	}
+	n.Children = nil
+	n.TextContent = ""
+}
+
+func (h *SelectHandler) Text(data string) {
+	parent := h.top()
+	parent.Children = append(parent.Children, &node.HTMLNode{Type: "text", TextContent: data, Parent: parent})
+}
+
+func (h *SelectHandler) Comment(string) {}
+func (h *SelectHandler) Doctype(string) {}
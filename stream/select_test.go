@@ -0,0 +1,54 @@
+package stream
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/EslamAhmed171/HTML-Parser/node"
+	"github.com/EslamAhmed171/HTML-Parser/selector"
+)
+
+func TestNewSelectHandlerRejectsLastChild(t *testing.T) {
+	sel, err := selector.Compile("li:last-child")
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if _, err := NewSelectHandler(sel, func(*node.HTMLNode) {}); err == nil {
+		t.Fatal("expected NewSelectHandler to reject a :last-child selector, got nil error")
+	}
+}
+
+func TestSelectHandlerMatchesInDocumentOrder(t *testing.T) {
+	sel, err := selector.Compile("li.x")
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	var got []string
+	emit := func(n *node.HTMLNode) { got = append(got, textOf(n)) }
+	h, err := NewSelectHandler(sel, emit)
+	if err != nil {
+		t.Fatalf("NewSelectHandler: %v", err)
+	}
+	if err := WalkStream(strings.NewReader(`<ul><li class="x">a</li><li>b</li><li class="x">c</li></ul>`), node.ParserConfig{}, h); err != nil {
+		t.Fatalf("WalkStream: %v", err)
+	}
+	want := []string{"a", "c"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func textOf(n *node.HTMLNode) string {
+	var b strings.Builder
+	var walk func(n *node.HTMLNode)
+	walk = func(n *node.HTMLNode) {
+		if n.Type == "text" {
+			b.WriteString(n.TextContent)
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(n)
+	return b.String()
+}
@@ -0,0 +1,124 @@
+// Package stream provides a SAX-style streaming mode on top of
+// html.NewTokenizer for documents too large to build entirely in memory,
+// plus handlers that consume those events: a constant-memory JSONL dump and
+// a selector-filtered mode that only materializes matching subtrees.
+package stream
+
+import (
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"github.com/EslamAhmed171/HTML-Parser/node"
+)
+
+// voidElements are the HTML5 elements that never have an end tag. The raw
+// tokenizer used by WalkStream has no tree-construction step to close these
+// implicitly the way html.Parse does, so WalkStream must do it itself.
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true,
+	"hr": true, "img": true, "input": true, "link": true, "meta": true,
+	"param": true, "source": true, "track": true, "wbr": true,
+}
+
+func isVoidElement(tag string) bool {
+	return voidElements[tag]
+}
+
+// Handler receives SAX-style events as WalkStream tokenizes a document.
+type Handler interface {
+	StartElement(tag string, attrs map[string]string)
+	EndElement(tag string)
+	Text(data string)
+	Comment(data string)
+	Doctype(data string)
+}
+
+// WalkStream tokenizes r and delivers StartElement/EndElement/Text/Comment/
+// Doctype events to h without ever building the full DOM in memory. Script
+// and style elements are skipped, mirroring node.ShouldSkipNode.
+func WalkStream(r io.Reader, cfg node.ParserConfig, h Handler) error {
+	z := html.NewTokenizer(r)
+	skipDepth := 0
+
+	for {
+		tokenType := z.Next()
+		switch tokenType {
+		case html.ErrorToken:
+			if err := z.Err(); err != io.EOF {
+				return err
+			}
+			return nil
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tt := z.Token()
+			tag := strings.ToLower(tt.Data)
+			// html.NewTokenizer only reports SelfClosingTagToken for the
+			// explicit "<tag/>" spelling; void elements written the normal
+			// way ("<img src=...>") come through as a plain StartTagToken
+			// with no matching EndTagToken ever following, so they must be
+			// treated as self-closed here regardless of the token's own flag.
+			selfClosing := tokenType == html.SelfClosingTagToken || isVoidElement(tag)
+			skip := tag == "script" || tag == "style"
+
+			attrs := make(map[string]string, len(tt.Attr))
+			for _, a := range tt.Attr {
+				key := strings.ToLower(a.Key)
+				if node.ShouldSkipAttribute(key) {
+					continue
+				}
+				attrs[key] = a.Val
+			}
+
+			if skip {
+				if !selfClosing {
+					skipDepth++
+				}
+				continue
+			}
+			if skipDepth > 0 {
+				continue
+			}
+			h.StartElement(tag, attrs)
+			if selfClosing {
+				h.EndElement(tag)
+			}
+
+		case html.EndTagToken:
+			tt := z.Token()
+			tag := strings.ToLower(tt.Data)
+			if tag == "script" || tag == "style" {
+				if skipDepth > 0 {
+					skipDepth--
+				}
+				continue
+			}
+			if skipDepth == 0 {
+				h.EndElement(tag)
+			}
+
+		case html.TextToken:
+			if skipDepth > 0 {
+				continue
+			}
+			text := string(z.Text())
+			if cfg.NormalizeWhitespace {
+				text = node.NormalizeText(text)
+			}
+			if text != "" {
+				h.Text(text)
+			}
+
+		case html.CommentToken:
+			if skipDepth == 0 {
+				h.Comment(string(z.Text()))
+			}
+
+		case html.DoctypeToken:
+			if skipDepth == 0 {
+				h.Doctype(string(z.Text()))
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package stream
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/EslamAhmed171/HTML-Parser/node"
+)
+
+type recordingHandler struct {
+	events []string
+}
+
+func (h *recordingHandler) StartElement(tag string, attrs map[string]string) {
+	h.events = append(h.events, "start:"+tag)
+}
+
+func (h *recordingHandler) EndElement(tag string) {
+	h.events = append(h.events, "end:"+tag)
+}
+
+func (h *recordingHandler) Text(data string) {
+	h.events = append(h.events, "text:"+data)
+}
+
+func (h *recordingHandler) Comment(data string) {
+	h.events = append(h.events, "comment:"+data)
+}
+
+func (h *recordingHandler) Doctype(data string) {
+	h.events = append(h.events, "doctype:"+data)
+}
+
+// TestWalkStreamClosesVoidElementsWithoutTrailingSlash is the maintainer's
+// repro: html.NewTokenizer never emits an EndTagToken for void elements like
+// <img> written without a trailing slash, so WalkStream must close them
+// itself or the next real end tag pops the wrong frame.
+func TestWalkStreamClosesVoidElementsWithoutTrailingSlash(t *testing.T) {
+	h := &recordingHandler{}
+	src := `<div><p>hi <img src="x.png"> there</p><p>second</p></div>`
+	if err := WalkStream(strings.NewReader(src), node.ParserConfig{}, h); err != nil {
+		t.Fatalf("WalkStream: %v", err)
+	}
+
+	want := []string{
+		"start:div",
+		"start:p",
+		"text:hi ",
+		"start:img",
+		"end:img",
+		"text: there",
+		"end:p",
+		"start:p",
+		"text:second",
+		"end:p",
+		"end:div",
+	}
+	if len(h.events) != len(want) {
+		t.Fatalf("got %v, want %v", h.events, want)
+	}
+	for i := range want {
+		if h.events[i] != want[i] {
+			t.Errorf("event %d: got %q, want %q (full: %v)", i, h.events[i], want[i], h.events)
+		}
+	}
+}